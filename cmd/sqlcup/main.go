@@ -2,24 +2,130 @@
 package main
 
 import (
+	"bufio"
 	_ "embed"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
 	"unicode"
+
+	"github.com/ngrash/sqlcup"
+)
+
+var (
+	noExistsClauseFlag      = flag.Bool("no-exists-clause", false, "Omit IF NOT EXISTS in CREATE TABLE statements")
+	viewFlag                = flag.Bool("view", false, "Only include SELECT statements for an existing view instead of a table")
+	withDropFlag            = flag.Bool("with-drop", false, "Include DROP TABLE statement before CREATE TABLE")
+	tablePrefixFlag         = flag.String("table-prefix", "", "Prepend `prefix` to the table name, but not to query names")
+	lowerSnakeTableFlag     = flag.Bool("lower-snake-table", false, "Name the table after <plural-name> in lower snake case, e.g. 'UserAccounts' becomes user_accounts")
+	descFlag                = flag.String("desc", "", "Start the schema and the queries with a comment containing `text`")
+	tableCommentFlag        = flag.String("table-comment", "", "Document the table with a comment in the schema")
+	schemaNameFlag          = flag.String("schema-name", "", "Qualify the table name with `schema` in the schema and the queries")
+	idFirstFlag             = flag.Bool("id-first", false, "Move id columns before all other columns")
+	strictColumnsFlag       = flag.Bool("strict-columns", false, "Reject tables that mix <plain-column>s and <smart-column>s")
+	plainNotNullDefaultFlag = flag.Bool("plain-not-null-default", false, "Make <plain-column>s NOT NULL unless their <type> ends with '?'")
+	idColumnFlag            = flag.String("id-column", "id", "Name of the column that identifies a row")
+	orderByFlag             = flag.String("order-by", "", "Include ORDER BY in 'SELECT *' statements, optionally per query: 'list=<terms>;listBy<Column>=<terms>'")
+	noReturningClauseFlag   = flag.Bool("no-returning-clause", false, "Omit 'RETURNING *' in UPDATE statement")
+	noCreateReturningFlag   = flag.Bool("no-create-returning", false, "Omit 'RETURNING *' in INSERT statement and annotate it ':exec'")
+	returningFlag           = flag.String("returning", "", "Comma-separated `columns` of the RETURNING clause instead of '*'")
+	onlyFlag                = flag.String("only", "", "Limit output to 'schema' or 'queries'")
+	schemaOnlyFlag          = flag.Bool("schema-only", false, "Same as '-only schema'")
+	queriesOnlyFlag         = flag.Bool("queries-only", false, "Same as '-only queries'")
+	getManyByIDFlag         = flag.Bool("get-many-by-id", false, "Include a 'SELECT * ... WHERE id IN (...)' statement for many ids")
+	withRowidGetFlag        = flag.Bool("with-rowid-get", false, "Include a 'SELECT *, rowid ... WHERE rowid = ?' statement (sqlite only)")
+	forUpdateFlag           = flag.Bool("for-update", false, "Include 'SELECT ... FOR UPDATE' statement (postgres and mysql only)")
+	testFixturesFlag        = flag.Int("test-fixtures", 0, "Print `n` INSERT statements with sample values to seed a development database")
+	withTruncateFlag        = flag.Bool("with-truncate", false, "Include a statement that deletes all rows")
+	noCountFlag             = flag.Bool("no-count", false, "Omit 'SELECT COUNT(*)' statement")
+	noGetFlag               = flag.Bool("no-get", false, "Omit the statements that select a row by id and check whether it exists")
+	noListFlag              = flag.Bool("no-list", false, "Omit the statements that select and count all rows")
+	noCreateFlag            = flag.Bool("no-create", false, "Omit the INSERT statement")
+	noUpdateFlag            = flag.Bool("no-update", false, "Omit the UPDATE statement")
+	noDeleteFlag            = flag.Bool("no-delete", false, "Omit the DELETE statement")
+	allowQuotedFlag         = flag.Bool("allow-quoted", false, "Quote invalid table and column names instead of rejecting them")
+	quoteIdentifiersFlag    = flag.Bool("quote-identifiers", false, "Quote all table and column names in the schema and the queries")
+	sectionOrderFlag        = flag.String("section-order", "schema,queries", "Order of the sections in the output: 'schema,queries' or 'queries,schema'")
+	noTrailingNewlineFlag   = flag.Bool("no-trailing-newline", false, "Omit the newline at the end of the output on stdout")
+	noBannersFlag           = flag.Bool("no-banners", false, "Omit the comments that introduce each section of the output")
+	indentFlag              = flag.String("indent", "2", "Indentation of generated SQL: '2', '4' or 'tab'")
+	maxLineWidthFlag        = flag.Int("max-line-width", 0, "Wrap column lists of INSERT statements at `width` characters (0 means unlimited)")
+	explicitColumnsFlag     = flag.Bool("explicit-columns", false, "List all columns in SELECT statements instead of '*'")
+	keywordCaseFlag         = flag.String("keyword-case", "upper", "Case of SQL keywords, also in generated constraints but not in column types and <plain-column> constraints: 'upper' or 'lower'")
+	entityCaseFlag          = flag.String("entity-case", "upper-camel", "Case of entity names in query names: 'upper-camel', 'snake' or 'raw'")
+	acronymsFlag            = flag.String("acronyms", "", "Comma-separated `words` to write in uppercase in upper camel case names, e.g. 'ID,API,URL,HTTP'")
+	alignConstraintsFlag    = flag.Bool("align-constraints", false, "Pad column constraints in CREATE TABLE statements to a common width")
+	nullAsPointerHintFlag   = flag.Bool("null-as-pointer-hint", false, "Mark nullable columns with a '-- nullable' comment in the schema")
+	uniqueAsIndexFlag       = flag.Bool("unique-as-index", false, "Create a unique index for each unique column instead of an inline UNIQUE constraint")
+	watchFlag               = flag.String("watch", "", "Regenerate the output in -output-dir whenever `file` changes, one table per line")
+	outputDirFlag           = flag.String("output-dir", "", "Append schema to `dir`/schema.sql and queries to dir/query/<table>.sql")
+	formatFlag              = flag.String("format", "text", "Output format: 'text' or 'json'")
+	schemaOutFlag           = flag.String("schema-out", "", "Append schema to `file` instead of printing it")
+	queriesOutFlag          = flag.String("queries-out", "", "Append queries to `file` instead of printing them")
+	createKindFlag          = flag.String("create-kind", "", "sqlc query `kind` of the INSERT statement: 'one', 'exec', 'execresult' or 'execrows'")
+	updateKindFlag          = flag.String("update-kind", "", "sqlc query `kind` of the UPDATE statement: 'one', 'exec', 'execresult' or 'execrows'")
+	partialUpdatesFlag      = flag.Bool("partial-updates", false, "Include an UPDATE statement for each column")
+	coalesceUpdateFlag      = flag.Bool("coalesce-update", false, "Keep columns unchanged in the UPDATE statement if their parameter is NULL")
+	getNameFlag             = flag.String("get-name", "Get{{.Singular}}", "Name `template` of the query that selects a row by id")
+	listNameFlag            = flag.String("list-name", "List{{.Plural}}", "Name `template` of the query that selects all rows")
+	createNameFlag          = flag.String("create-name", "Create{{.Singular}}", "Name `template` of the query that inserts a row")
+	deleteNameFlag          = flag.String("delete-name", "Delete{{.Singular}}", "Name `template` of the query that deletes a row by id")
+	updateNameFlag          = flag.String("update-name", "Update{{.Singular}}", "Name `template` of the query that updates a row by id")
+	appendFlag              = flag.Bool("append", false, "Skip queries already defined in the -queries-out file")
+	softDeleteFlag          = flag.Bool("soft-delete", false, "Mark rows as deleted in a deleted_at column instead of deleting them")
+	timestampsFlag          = flag.Bool("timestamps", false, "Add created_at and updated_at columns")
+	batchInsertFlag         = flag.Bool("batch-insert", false, "Include a bulk INSERT statement annotated ':copyfrom' (postgres) or ':batchexec'")
+	upsertFlag              = flag.Bool("upsert", false, "Include INSERT ... ON CONFLICT statement")
+	excludeFromInsertFlag   = flag.String("exclude-from-insert", "", "Comma-separated `columns` to omit from INSERT and UPDATE statements, like @readonly")
+	filterByFlag            = flag.String("filter-by", "", "Comma-separated `columns` to include a 'SELECT * ... WHERE <column> = ?' statement for")
+	upsertConflictFlag      = flag.String("upsert-conflict", "", "Comma-separated conflict target `columns` of the upsert statement (default id or first unique column)")
+	paginateFlag            = flag.Bool("paginate", false, "Include LIMIT and OFFSET in 'SELECT *' statement")
+	keysetFlag              = flag.Bool("keyset", false, "Include a 'SELECT * ... WHERE id > ? ORDER BY id LIMIT ?' statement for keyset pagination")
+	namedParamsFlag         = flag.Bool("named-params", false, "Use sqlc.arg() named parameters for filters, LIMIT and OFFSET in 'SELECT *' statements")
+	sqliteVersionFlag       = flag.String("sqlite-version", "", "Target SQLite `version`, e.g. '3.30', omitting RETURNING clauses before 3.35")
+	dialectFlag             = flag.String("dialect", "sqlite", "SQL dialect of generated statements: 'sqlite', 'postgres' or 'mysql'")
+	emitSqlcConfigFlag      = flag.Bool("emit-sqlc-config", false, "Write a minimal sqlc.yaml for the schema and queries unless it exists")
+	stampFlag               = flag.Bool("stamp", false, "Include a comment with the sqlcup version and arguments above the first query")
+	stdinJSONFlag           = flag.Bool("stdin-json", false, "Read a single table as JSON from stdin instead of <entity-name> and <column> arguments")
+	dryRunFlag              = flag.Bool("dry-run", false, "Validate all arguments without printing or writing SQL")
+	verboseFlag             = flag.Bool("v", false, "Describe each parsed column on stderr")
+	placeholderStyleFlag    = flag.String("placeholder-style", "", "Render parameters as 'question' (?) or 'dollar' ($1) (default depends on -dialect)")
 )
 
+func init() {
+	flag.Func("engine", "Same as -dialect, but with the `engine` names of sqlc: 'sqlite', 'postgresql' or 'mysql'", setEngine)
+	flag.Var(&indexFlag, "index", "Comma-separated `columns` of an index to create after the table (repeatable)")
+	flag.Var(&uniqueFlag, "unique", "Comma-separated `columns` of a UNIQUE table constraint (repeatable)")
+}
+
 var (
-	noExistsClauseFlag    = flag.Bool("no-exists-clause", false, "Omit IF NOT EXISTS in CREATE TABLE statements")
-	idColumnFlag          = flag.String("id-column", "id", "Name of the column that identifies a row")
-	orderByFlag           = flag.String("order-by", "", "Include ORDER BY in 'SELECT *' statement")
-	noReturningClauseFlag = flag.Bool("no-returning-clause", false, "Omit 'RETURNING *' in UPDATE statement")
-	onlyFlag              = flag.String("only", "", "Limit output to 'schema' or 'queries'")
+	indexFlag  stringListFlag
+	uniqueFlag stringListFlag
 )
 
+// stringListFlag is a flag.Value that collects the values of a repeatable flag.
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 const (
 	plainColumnSep = ":"
 	smartColumnSep = "@"
@@ -31,11 +137,19 @@ const (
 )
 
 var (
-	errBadArgument        = errors.New("bad argument")
+	errBadArgument        = sqlcup.ErrBadArgument
 	errInvalidSmartColumn = fmt.Errorf("%w: invalid <smart-column>", errBadArgument)
 )
 
+// version is the version of sqlcup, set at build time with -ldflags "-X main.version=<version>".
+// If unset, the module version from the build info is used.
+var version = ""
+
+// configFile is the name of the file in the current directory that sets default flags.
+const configFile = ".sqlcup.yaml"
+
 // usage contains the inline documentation for sqlcup.
+//
 //go:embed usage.txt
 var usage string
 
@@ -44,6 +158,10 @@ func main() {
 	flag.CommandLine.SetOutput(io.Discard)
 	// With flag.ContinueOnError we prevent Parse from calling os.Exit on error and instead show our own error message.
 	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	// Flags from the config file act as defaults that the command line overrides.
+	if err := loadConfig(configFile); err != nil {
+		exitWithError(err)
+	}
 	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
 		if err == flag.ErrHelp {
 			printHelp()
@@ -52,18 +170,536 @@ func main() {
 		fatalUsageError(err)
 	}
 
-	sca, err := parseScaffoldCommandArgs(flag.CommandLine.Args())
+	// 'completion' is no valid <entity-name>, because it cannot be singularized.
+	if args := flag.CommandLine.Args(); len(args) > 0 && args[0] == "completion" {
+		if err := completionCommand(os.Stdout, args[1:]); err != nil {
+			exitWithError(err)
+		}
+		return
+	}
+
+	// The engine of an existing sqlc configuration is the default dialect, unless the config file or the command line sets one.
+	if !isFlagSet("dialect") {
+		dialect, err := detectEngine(sqlcConfigFile)
+		if err != nil {
+			//goland:noinspection GoUnhandledErrorResult
+			fmt.Fprintf(os.Stderr, "%s: note: not detecting -dialect, %v\n", os.Args[0], err)
+		} else if dialect != "" {
+			*dialectFlag = dialect
+		}
+	}
+
+	if *watchFlag != "" {
+		if err := watchCommand(*watchFlag, flag.CommandLine.Args(), watchInterval); err != nil {
+			exitWithError(err)
+		}
+		return
+	}
+
+	var (
+		tables []*scaffoldCommandArgs
+		err    error
+	)
+	if *stdinJSONFlag {
+		tables, err = parseStdinJSON(flag.CommandLine.Args())
+	} else {
+		tables, err = parseScaffoldCommandArgs(flag.CommandLine.Args())
+	}
 	if err != nil {
 		exitWithError(err)
 	}
+	if *verboseFlag {
+		describeColumns(os.Stderr, tables)
+	}
 
-	err = scaffoldCommand(sca)
+	if *dryRunFlag {
+		err = dryRun(tables)
+	} else {
+		err = scaffoldCommand(os.Stdout, tables)
+	}
+	if err == nil && *emitSqlcConfigFlag && !*dryRunFlag {
+		err = emitSqlcConfig(sqlcConfigFile, tables)
+	}
 	if err != nil {
 		exitWithError(err)
 	}
 }
 
+// watchInterval is the time between two checks of the -watch file for changes.
+const watchInterval = 500 * time.Millisecond
+
+// watchCommand regenerates the output of the tables in the spec file at path whenever its modification time or size changes.
+// It only returns if path cannot be read. Other errors are printed, and the output is regenerated after the next change.
+func watchCommand(path string, args []string, interval time.Duration) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: cannot combine '-watch' with <entity-name> and <column> arguments", errBadArgument)
+	}
+	if *outputDirFlag == "" {
+		return fmt.Errorf("%w: '-watch' requires '-output-dir'", errBadArgument)
+	}
+	var (
+		modTime time.Time
+		size    int64 = -1
+		written map[string]string
+	)
+	for {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !fi.ModTime().Equal(modTime) || fi.Size() != size {
+			modTime, size = fi.ModTime(), fi.Size()
+			written, err = regenerate(path, written)
+			if err != nil {
+				//goland:noinspection GoUnhandledErrorResult
+				fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+			}
+		}
+		time.Sleep(interval)
+	}
+}
+
+// regenerate writes the output of the tables in the spec file at path, replacing the output written before.
+// Both previous and the result map each file to the content appended to it, so that content the files had before is kept.
+// It returns previous if the spec file is invalid.
+func regenerate(path string, previous map[string]string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return previous, err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer f.Close()
+	specs, err := readTableSpecs(f)
+	if err != nil {
+		return previous, err
+	}
+	var tables []*scaffoldCommandArgs
+	for _, spec := range specs {
+		sca, err := parseTableArgs(spec, false)
+		if err != nil {
+			return previous, err
+		}
+		tables = append(tables, sca)
+	}
+
+	// The output is appended to the files, so the output of tables that changed or were removed goes first.
+	for name, section := range previous {
+		if err := removeSection(name, section); err != nil {
+			return previous, err
+		}
+	}
+	sizes := make(map[string]int64)
+	for _, sca := range tables {
+		for _, name := range []string{sca.SchemaOut, sca.QueriesOut} {
+			fi, err := os.Stat(name)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return previous, err
+			}
+			sizes[name] = 0
+			if err == nil {
+				sizes[name] = fi.Size()
+			}
+		}
+	}
+	err = scaffoldCommand(os.Stdout, tables)
+
+	written := make(map[string]string)
+	for name, size := range sizes {
+		content, rerr := os.ReadFile(name)
+		if errors.Is(rerr, os.ErrNotExist) {
+			continue
+		}
+		if rerr != nil {
+			return written, rerr
+		}
+		if int64(len(content)) > size {
+			written[name] = string(content[size:])
+		}
+	}
+	return written, err
+}
+
+// removeSection removes the last occurrence of section from the file at path, and the file if nothing else remains.
+// A missing file, or a file that no longer contains section, is left alone.
+func removeSection(path, section string) error {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	i := strings.LastIndex(string(content), section)
+	if i < 0 {
+		return nil
+	}
+	rest := string(content[:i]) + string(content[i+len(section):])
+	if rest == "" {
+		return os.Remove(path)
+	}
+	return os.WriteFile(path, []byte(rest), 0644)
+}
+
+// readTableSpecs reads the arguments of one table per line from r, like '<entity-name> <column> ...'.
+// Blank lines and lines starting with # are skipped.
+func readTableSpecs(r io.Reader) ([][]string, error) {
+	lines, err := readColumnDefinitions(r)
+	if err != nil {
+		return nil, err
+	}
+	var specs [][]string
+	for _, line := range lines {
+		fields, err := splitFields(line)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, fields)
+	}
+	return specs, nil
+}
+
+// splitFields splits line at spaces and tabs like a shell, except within single or double quotes.
+func splitFields(line string) ([]string, error) {
+	var (
+		fields  []string
+		field   strings.Builder
+		quote   rune
+		inField bool
+	)
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			field.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inField = r, true
+		case r == ' ' || r == '\t':
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("%w: unterminated quote in '%s'", errBadArgument, line)
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
+
+// engineDialects maps the engines of sqlc to the values of -dialect.
+var engineDialects = map[string]string{
+	"sqlite":     "sqlite",
+	"postgresql": "postgres",
+	"mysql":      "mysql",
+}
+
+// setEngine sets -dialect to the dialect of the sqlc engine.
+func setEngine(engine string) error {
+	dialect, ok := engineDialects[engine]
+	if !ok {
+		return fmt.Errorf("%w: '-engine %s', expected 'sqlite', 'postgresql' or 'mysql'", errBadArgument, engine)
+	}
+	return flag.CommandLine.Set("dialect", dialect)
+}
+
+// isFlagSet reports whether the named flag was set by the config file or the command line.
+// Setting -engine also sets -dialect.
+func isFlagSet(name string) bool {
+	set := false
+	flag.CommandLine.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// detectEngine returns the dialect of the engine of the sqlc configuration at path.
+// It returns an empty dialect if the file does not exist or uses more than one engine.
+func detectEngine(path string) (string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer f.Close()
+
+	engines, err := readEngines(f)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", path, err)
+	}
+	if len(engines) != 1 {
+		return "", nil
+	}
+	dialect, ok := engineDialects[engines[0]]
+	if !ok {
+		return "", fmt.Errorf("%s: unknown engine '%s', expected 'sqlite', 'postgresql' or 'mysql'", path, engines[0])
+	}
+	return dialect, nil
+}
+
+// readEngines returns the distinct values of all 'engine:' keys of the sqlc configuration read from r.
+func readEngines(r io.Reader) ([]string, error) {
+	var engines []string
+	seen := make(map[string]bool)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimPrefix(strings.TrimSpace(sc.Text()), "- ")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "engine" {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if !seen[value] {
+			seen[value] = true
+			engines = append(engines, value)
+		}
+	}
+	return engines, sc.Err()
+}
+
+// sqlcConfigFile is the file written by -emit-sqlc-config.
+const sqlcConfigFile = "sqlc.yaml"
+
+// emitSqlcConfig writes the sqlc configuration for tables to path unless the file already exists.
+//
+//goland:noinspection GoUnhandledErrorResult
+func emitSqlcConfig(path string, tables []*scaffoldCommandArgs) (err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if errors.Is(err, os.ErrExist) {
+		fmt.Fprintf(os.Stderr, "%s: not writing %s, file already exists\n", os.Args[0], path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	if _, err = f.WriteString(sqlcConfig(tables)); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s: wrote %s\n", os.Args[0], path)
+	return nil
+}
+
+// sqlcConfig returns a minimal sqlc.yaml for the schema and queries of tables.
+// Printed sections are assumed to be added to schema.sql and query.sql.
+func sqlcConfig(tables []*scaffoldCommandArgs) string {
+	engine := map[sqlcup.Dialect]string{
+		sqlcup.DialectSQLite:   "sqlite",
+		sqlcup.DialectPostgres: "postgresql",
+		sqlcup.DialectMySQL:    "mysql",
+	}[tables[0].Dialect]
+	var schemas, queries []string
+	for _, args := range tables {
+		schema, query := "schema.sql", "query.sql"
+		if args.SchemaOut != "" {
+			schema = filepath.ToSlash(args.SchemaOut)
+		}
+		if args.QueriesOut != "" {
+			query = filepath.ToSlash(args.QueriesOut)
+		}
+		if *outputDirFlag != "" {
+			// Each table has its own queries file in the query directory.
+			query = filepath.ToSlash(filepath.Dir(args.QueriesOut))
+		}
+		schemas = appendUnique(schemas, schema)
+		queries = appendUnique(queries, query)
+	}
+	return fmt.Sprintf(`version: "2"
+sql:
+  - engine: "%s"
+    schema: %s
+    queries: %s
+    gen:
+      go:
+        package: "db"
+        out: "db"
+`, engine, yamlPaths(schemas), yamlPaths(queries))
+}
+
+// appendUnique appends s to list unless list already contains it.
+func appendUnique(list []string, s string) []string {
+	for _, e := range list {
+		if e == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// yamlPaths returns paths as a YAML value: a single string, or a list of strings if there are several.
+func yamlPaths(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = `"` + path + `"`
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// dryRun validates the arguments of all tables and reports the number of parsed columns to os.Stderr.
+//
+//goland:noinspection GoUnhandledErrorResult
+func dryRun(tables []*scaffoldCommandArgs) error {
+	n := 0
+	for _, args := range tables {
+		if err := sqlcup.Validate(args.Args); err != nil {
+			return err
+		}
+		n += len(args.Columns)
+	}
+	fmt.Fprintf(os.Stderr, "ok: %d columns parsed\n", n)
+	return nil
+}
+
+// completionCommand writes a completion script for the shell named by args to w.
+//
+//goland:noinspection GoUnhandledErrorResult
+func completionCommand(w io.Writer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected 'completion bash|zsh|fish'", errBadArgument)
+	}
+	var flags []*flag.Flag
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, f)
+	})
+	switch args[0] {
+	case "bash":
+		var names []string
+		for _, f := range flags {
+			names = append(names, "-"+f.Name)
+		}
+		fmt.Fprintf(w, `_sqlcup() {
+  COMPREPLY=($(compgen -W "completion %s" -- "${COMP_WORDS[COMP_CWORD]}"))
+}
+complete -o default -F _sqlcup sqlcup
+`, strings.Join(names, " "))
+	case "zsh":
+		fmt.Fprintln(w, "#compdef sqlcup")
+		fmt.Fprintln(w, "_arguments \\")
+		// Brackets and colons delimit the parts of an _arguments spec.
+		escape := strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`)
+		for _, f := range flags {
+			_, usage := flag.UnquoteUsage(f)
+			fmt.Fprintf(w, "  '-%s[%s]' \\\n", f.Name, escape.Replace(usage))
+		}
+		fmt.Fprintln(w, "  '*::argument:_default'")
+	case "fish":
+		escape := strings.NewReplacer(`\`, `\\`, "'", `\'`)
+		for _, f := range flags {
+			_, usage := flag.UnquoteUsage(f)
+			fmt.Fprintf(w, "complete -c sqlcup -o %s -d '%s'\n", f.Name, escape.Replace(usage))
+		}
+	default:
+		return fmt.Errorf("%w: 'completion %s', expected 'bash', 'zsh' or 'fish'", errBadArgument, args[0])
+	}
+	return nil
+}
+
+// describeColumns writes a line for each parsed column of tables to w.
+//
+//goland:noinspection GoUnhandledErrorResult
+func describeColumns(w io.Writer, tables []*scaffoldCommandArgs) {
+	for _, args := range tables {
+		fmt.Fprintf(w, "table '%s'\n", args.Table)
+		for _, col := range args.Columns {
+			fmt.Fprintf(w, "col '%s' -> %s, constraint '%s', id=%t\n", col.Name, col.Type, col.Constraint, col.ID)
+		}
+	}
+}
+
+// noteMissingID writes a note to w if the queries of the table are generated, but none of cols is an id column.
+// Without an id, rows cannot be addressed, so the queries on single rows are missing from the output.
+//
+//goland:noinspection GoUnhandledErrorResult
+func noteMissingID(w io.Writer, sca *scaffoldCommandArgs, cols []sqlcup.Column) {
+	if sca.Output&outputQueries == 0 {
+		return
+	}
+	for _, col := range cols {
+		if col.ID {
+			return
+		}
+	}
+	fmt.Fprintf(w, "%s: note: no id column detected in table %s; skipping Get/Exists/Update/Delete\n", os.Args[0], sca.Table)
+}
+
+// loadConfig sets the flags defined in the config file at path. A missing file sets no flags.
+func loadConfig(path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer f.Close()
+
+	settings, err := readConfig(f)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", errBadArgument, path, err)
+	}
+	for _, s := range settings {
+		if flag.CommandLine.Lookup(s.name) == nil {
+			return fmt.Errorf("%w: %s:%d: unknown flag '%s'", errBadArgument, path, s.line, s.name)
+		}
+		if err := flag.CommandLine.Set(s.name, s.value); err != nil {
+			return fmt.Errorf("%w: %s:%d: '%s: %s', %v", errBadArgument, path, s.line, s.name, s.value, err)
+		}
+	}
+	return nil
+}
+
+// configSetting is a single flag set by the config file.
+type configSetting struct {
+	line  int
+	name  string
+	value string
+}
+
+// readConfig reads one '<flag>: <value>' setting per line from r.
+// Blank lines and lines starting with # are skipped. Values may be enclosed in quotes.
+func readConfig(r io.Reader) ([]configSetting, error) {
+	var settings []configSetting
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: '%s', expected '<flag>: <value>'", n, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		settings = append(settings, configSetting{line: n, name: strings.TrimSpace(name), value: value})
+	}
+	return settings, sc.Err()
+}
+
 // fatalUsageError writes the inline help to os.Stdout and the err to os.Stderr, then calls os.Exit(1).
+//
 //goland:noinspection GoUnhandledErrorResult
 func fatalUsageError(err error) {
 	printHelp()
@@ -89,6 +725,7 @@ func printHelp() {
 
 // exitWithError prints err to os.Stderr and calls os.Exit.
 // If err is (or wraps) errBadArgument, inline documentation is written to os.Stdout.
+//
 //goland:noinspection GoUnhandledErrorResult
 func exitWithError(err error) {
 	if errors.Is(err, errBadArgument) {
@@ -100,12 +737,12 @@ func exitWithError(err error) {
 	}
 }
 
-type column struct {
-	Name       string
-	Type       string
-	Constraint string
-	ID         bool
-}
+type outputFormat uint8
+
+const (
+	formatText outputFormat = iota
+	formatJSON
+)
 
 type outputMode uint8
 
@@ -117,59 +754,151 @@ const (
 )
 
 type scaffoldCommandArgs struct {
-	Table             string
-	SingularEntity    string
-	PluralEntity      string
-	IDColumn          *column
-	Columns           []column
-	NonIDColumns      []column
-	LongestName       int
-	LongestType       int
-	NoExistsClause    bool
-	OrderBy           string
-	NoReturningClause bool
-	Output            outputMode
-}
-
-func parseColumnDefinition(s string) (column, error) {
+	sqlcup.Args
+	Output     outputMode
+	Format     outputFormat
+	SchemaOut  string
+	QueriesOut string
+	Append     bool
+	// Desc describes the table in a comment above its schema and its first query.
+	Desc string
+	// Fixtures is the number of sample rows printed with -test-fixtures.
+	Fixtures int
+	// QueriesFirst prints the queries before the schema.
+	QueriesFirst bool
+}
+
+func parseColumnDefinition(s string, d sqlcup.Dialect, lower bool) (sqlcup.Column, error) {
 	var (
 		plainColumn = strings.Contains(s, plainColumnSep)
 		smartColumn = strings.Contains(s, smartColumnSep)
 	)
 	if plainColumn && smartColumn {
-		return column{}, fmt.Errorf("%w: invalid <column>: '%s' contains both plain and smart separators", errBadArgument, s)
+		return sqlcup.Column{}, fmt.Errorf("%w: invalid <column>: '%s' contains both plain and smart separators", errBadArgument, s)
 	}
 	if plainColumn {
 		return parsePlainColumnDefinition(s)
 	} else if smartColumn {
-		return parseSmartColumnDefinition(s)
+		return parseSmartColumnDefinition(s, d, lower)
 	}
-	return column{}, fmt.Errorf("%w: invalid <column>: '%s', expected <smart-column> or <plain-column>", errBadArgument, s)
+	return sqlcup.Column{}, fmt.Errorf("%w: invalid <column>: '%s', expected <smart-column> or <plain-column>", errBadArgument, s)
 }
 
-func parseSmartColumnDefinition(s string) (column, error) {
+func parseSmartColumnDefinition(s string, d sqlcup.Dialect, lower bool) (sqlcup.Column, error) {
+	// kw applies -keyword-case to the keywords of the constraint, but not to the values of tags.
+	kw := func(keyword string) string {
+		if lower {
+			return strings.ToLower(keyword)
+		}
+		return keyword
+	}
 	if s == "@id" {
-		return column{
-			ID:         true,
-			Name:       "id",
-			Type:       "INTEGER",
-			Constraint: "PRIMARY KEY",
-		}, nil
+		// The single tag @id is a shortcut for a column named 'id'.
+		s = "id@id"
 	}
 
 	name, rest, _ := strings.Cut(s, smartColumnSep)
 	if name == "" {
-		return column{}, fmt.Errorf("%w: '%s', missing <name>", errInvalidSmartColumn, s)
+		return sqlcup.Column{}, fmt.Errorf("%w: '%s', missing <name>", errInvalidSmartColumn, s)
 	}
 
 	var (
-		colType string
-		id      bool
-		null    bool
-		unique  bool
+		colType      string
+		id           bool
+		null         bool
+		unique       bool
+		autoinc      bool
+		defaultValue string
+		references   string
+		onDelete     string
+		onUpdate     string
+		check        string
+		generated    string
+		virtual      bool
+		enum         []string
+		comment      string
+		tz           bool
+		readOnly     bool
 	)
-	tags := strings.Split(rest, smartColumnSep)
+	tags := splitSmartColumnTags(rest)
 	for _, tag := range tags {
+		// Tags of the form <key>=<value> carry an argument.
+		key, value, hasValue := strings.Cut(tag, "=")
+		switch key {
+		case "default":
+			if value == "" {
+				return sqlcup.Column{}, fmt.Errorf("%w: '%s', missing <value> in @default=<value>", errInvalidSmartColumn, s)
+			}
+			defaultValue = value
+			continue
+		case "references", "fk":
+			table, col, ok := strings.Cut(value, ".")
+			if !ok || table == "" || col == "" || strings.Contains(col, ".") {
+				return sqlcup.Column{}, fmt.Errorf("%w: '%s', expected @%s=<table>.<column>", errInvalidSmartColumn, s, key)
+			}
+			references = fmt.Sprintf(kw("REFERENCES")+" %s(%s)", table, col)
+			continue
+		case "on-delete", "on-update":
+			action, ok := referentialActions[value]
+			if !ok {
+				return sqlcup.Column{}, fmt.Errorf("%w: '%s', expected @%s=<action> with 'cascade', 'set-null', 'restrict' or 'no-action'", errInvalidSmartColumn, s, key)
+			}
+			if key == "on-delete" {
+				onDelete = kw(" ON DELETE " + action)
+			} else {
+				onUpdate = kw(" ON UPDATE " + action)
+			}
+			continue
+		case "varchar":
+			if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+				return sqlcup.Column{}, fmt.Errorf("%w: '%s', expected @varchar=<length> with positive integer <length>", errInvalidSmartColumn, s)
+			}
+			colType = "VARCHAR(" + value + ")"
+			continue
+		case "decimal":
+			if !hasValue {
+				colType = "DECIMAL"
+				continue
+			}
+			precision, scale, ok := strings.Cut(value, ",")
+			p, perr := strconv.Atoi(precision)
+			sc, serr := strconv.Atoi(scale)
+			if !ok || perr != nil || serr != nil || p <= 0 || sc < 0 || sc > p {
+				return sqlcup.Column{}, fmt.Errorf("%w: '%s', expected @decimal=<precision>,<scale>", errInvalidSmartColumn, s)
+			}
+			colType = fmt.Sprintf("DECIMAL(%d,%d)", p, sc)
+			continue
+		case "check":
+			if value == "" {
+				return sqlcup.Column{}, fmt.Errorf("%w: '%s', missing <expr> in @check=<expr>", errInvalidSmartColumn, s)
+			}
+			check = kw("CHECK") + " (" + value + ")"
+			continue
+		case "comment":
+			if value == "" {
+				return sqlcup.Column{}, fmt.Errorf("%w: '%s', missing <text> in @comment=<text>", errInvalidSmartColumn, s)
+			}
+			comment = value
+			continue
+		case "enum":
+			enum = strings.Split(value, ",")
+			for _, v := range enum {
+				if v == "" {
+					return sqlcup.Column{}, fmt.Errorf("%w: '%s', expected @enum=<value>,...", errInvalidSmartColumn, s)
+				}
+			}
+			continue
+		case "generated":
+			if value == "" {
+				return sqlcup.Column{}, fmt.Errorf("%w: '%s', missing <expr> in @generated=<expr>", errInvalidSmartColumn, s)
+			}
+			generated = value
+			continue
+		}
+		if hasValue {
+			return sqlcup.Column{}, fmt.Errorf("%w: '%s', <tag> @%s does not take a value", errInvalidSmartColumn, s, key)
+		}
+
 		switch tag {
 		case "id":
 			id = true
@@ -177,95 +906,589 @@ func parseSmartColumnDefinition(s string) (column, error) {
 			null = true
 		case "unique":
 			unique = true
+		case "autoincrement":
+			autoinc = true
+		case "virtual":
+			virtual = true
+		case "readonly":
+			readOnly = true
 		case "float":
 			colType = "FLOAT"
 		case "double":
 			colType = "DOUBLE"
 		case "datetime":
-			colType = "DATETIME"
+			// PostgreSQL has no DATETIME type.
+			if d == sqlcup.DialectPostgres {
+				colType = "TIMESTAMP"
+			} else {
+				colType = "DATETIME"
+			}
+		case "tz":
+			tz = true
 		case "text":
 			colType = "TEXT"
 		case "int":
 			colType = "INTEGER"
+		case "bigint":
+			colType = "BIGINT"
+		case "smallint":
+			colType = "SMALLINT"
 		case "blob":
 			colType = "BLOB"
+		case "bool":
+			if d == sqlcup.DialectMySQL {
+				colType = "TINYINT(1)"
+			} else {
+				colType = "BOOLEAN"
+			}
+		case "uuid":
+			switch d {
+			case sqlcup.DialectPostgres:
+				colType = "UUID"
+			case sqlcup.DialectMySQL:
+				colType = "CHAR(36)"
+			default:
+				colType = "TEXT"
+			}
+		case "json", "jsonb":
+			switch d {
+			case sqlcup.DialectPostgres:
+				colType = strings.ToUpper(tag)
+			case sqlcup.DialectMySQL:
+				colType = "JSON"
+			default:
+				colType = "TEXT"
+			}
 		default:
-			return column{}, fmt.Errorf("%w: '%s', unknown <tag> #%s", errInvalidSmartColumn, s, tag)
+			return sqlcup.Column{}, fmt.Errorf("%w: '%s', unknown <tag> #%s", errInvalidSmartColumn, s, tag)
+		}
+	}
+	if onDelete != "" || onUpdate != "" {
+		if references == "" {
+			return sqlcup.Column{}, fmt.Errorf("%w: '%s', @on-delete and @on-update require @fk=<table>.<column>", errInvalidSmartColumn, s)
+		}
+		references += onDelete + onUpdate
+	}
+	if autoinc && !id {
+		return sqlcup.Column{}, fmt.Errorf("%w: '%s', @autoincrement requires @id", errInvalidSmartColumn, s)
+	}
+	if autoinc && d != sqlcup.DialectSQLite {
+		return sqlcup.Column{}, fmt.Errorf("%w: '%s', @autoincrement is only supported by sqlite", errInvalidSmartColumn, s)
+	}
+	if tz {
+		if colType != "TIMESTAMP" && colType != "DATETIME" {
+			return sqlcup.Column{}, fmt.Errorf("%w: '%s', @tz requires @datetime", errInvalidSmartColumn, s)
+		}
+		if d != sqlcup.DialectPostgres {
+			return sqlcup.Column{}, fmt.Errorf("%w: '%s', @tz is only supported by postgres", errInvalidSmartColumn, s)
+		}
+		colType = "TIMESTAMPTZ"
+	}
+	if colType == "BOOLEAN" || colType == "TINYINT(1)" {
+		defaultValue = boolLiteral(defaultValue, d)
+		if defaultValue == "TRUE" || defaultValue == "FALSE" {
+			defaultValue = kw(defaultValue)
+		}
+	}
+	if virtual && generated == "" {
+		return sqlcup.Column{}, fmt.Errorf("%w: '%s', @virtual requires @generated=<expr>", errInvalidSmartColumn, s)
+	}
+	if generated != "" {
+		if id || defaultValue != "" {
+			return sqlcup.Column{}, fmt.Errorf("%w: '%s', cannot combine @generated with @id or @default", errInvalidSmartColumn, s)
+		}
+		if virtual && d == sqlcup.DialectPostgres {
+			return sqlcup.Column{}, fmt.Errorf("%w: '%s', @virtual is not supported by postgres", errInvalidSmartColumn, s)
+		}
+	}
+	if enum != nil {
+		if id || colType != "" {
+			return sqlcup.Column{}, fmt.Errorf("%w: '%s', cannot combine @enum with @id or a column type", errInvalidSmartColumn, s)
 		}
 	}
 	if id {
 		if unique || null {
-			return column{}, fmt.Errorf("%w: '%s', cannot combine @id with @unique or @null", errInvalidSmartColumn, s)
+			return sqlcup.Column{}, fmt.Errorf("%w: '%s', cannot combine @id with @unique or @null", errInvalidSmartColumn, s)
 		}
 		if colType == "" {
 			colType = "INTEGER"
 		}
-		// sqlite special case
-		var constraint = "PRIMARY KEY"
-		if colType != "INTEGER" {
-			constraint = "NOT NULL " + constraint
+		var constraint = kw("PRIMARY KEY")
+		switch d {
+		case sqlcup.DialectPostgres:
+			// PostgreSQL uses pseudo-types for auto-incrementing integers.
+			switch colType {
+			case "INTEGER":
+				colType = "SERIAL"
+			case "BIGINT":
+				colType = "BIGSERIAL"
+			case "SMALLINT":
+				colType = "SMALLSERIAL"
+			}
+		case sqlcup.DialectMySQL:
+			switch colType {
+			case "INTEGER":
+				colType = "INT"
+				constraint = kw("AUTO_INCREMENT ") + constraint
+			case "BIGINT", "SMALLINT":
+				constraint = kw("AUTO_INCREMENT ") + constraint
+			}
+		case sqlcup.DialectSQLite:
+			// Only INTEGER PRIMARY KEY is an alias for the rowid and therefore implicitly NOT NULL.
+			// The rowid is a 64-bit integer, so other integer sizes collapse to INTEGER.
+			if colType == "BIGINT" || colType == "SMALLINT" {
+				colType = "INTEGER"
+			}
+			if colType != "INTEGER" {
+				constraint = kw("NOT NULL ") + constraint
+			}
+			if autoinc {
+				// AUTOINCREMENT prevents the reuse of rowids, but is only allowed for the rowid alias.
+				if colType != "INTEGER" {
+					return sqlcup.Column{}, fmt.Errorf("%w: '%s', @autoincrement requires an integer type", errInvalidSmartColumn, s)
+				}
+				constraint += kw(" AUTOINCREMENT")
+			}
+		}
+		if defaultValue != "" {
+			constraint += kw(" DEFAULT ") + defaultValue
+		}
+		if references != "" {
+			constraint += " " + references
 		}
-		return column{
+		if check != "" {
+			constraint += " " + check
+		}
+		return sqlcup.Column{
 			Name:       name,
 			Type:       colType,
 			Constraint: constraint,
 			ID:         true,
+			Comment:    comment,
+			ReadOnly:   readOnly,
 		}, nil
 	}
 
-	if colType == "" {
-		return column{}, fmt.Errorf("%w: '%s', missing column type", errInvalidSmartColumn, s)
+	// The type of an enumerated column depends on the table, so it is left to sqlcup.
+	if colType == "" && enum == nil {
+		return sqlcup.Column{}, fmt.Errorf("%w: '%s', missing column type", errInvalidSmartColumn, s)
 	}
 	constraint := ""
+	if generated != "" {
+		// MySQL requires the generation clause to directly follow the column type.
+		constraint += kw(" GENERATED ALWAYS AS") + " (" + generated + ")"
+		if virtual {
+			constraint += kw(" VIRTUAL")
+		} else {
+			constraint += kw(" STORED")
+		}
+	}
 	if !null {
-		constraint += " NOT NULL"
+		constraint += kw(" NOT NULL")
+	}
+	if defaultValue != "" {
+		constraint += kw(" DEFAULT ") + defaultValue
 	}
 	if unique {
-		constraint += " UNIQUE"
+		constraint += kw(" UNIQUE")
+	}
+	if references != "" {
+		constraint += " " + references
 	}
-	return column{
+	if check != "" {
+		constraint += " " + check
+	}
+	return sqlcup.Column{
 		Name:       name,
 		Type:       colType,
 		Constraint: strings.TrimSpace(constraint),
 		ID:         false,
+		Unique:     unique,
+		// Generated columns cannot be written.
+		ReadOnly: readOnly || generated != "",
+		Enum:     enum,
+		Comment:  comment,
 	}, nil
 }
 
-func parsePlainColumnDefinition(s string) (column, error) {
-	parts := strings.Split(s, ":")
-	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
-		return column{}, fmt.Errorf("%w: invalid <plain-column>: '%s', expected '<name>:<type>[:<constraint>]'", errBadArgument, s)
-	}
-	col := column{
-		ID:   strings.ToLower(parts[0]) == *idColumnFlag,
-		Name: parts[0],
-		Type: parts[1],
+// boolLiteral returns value as a boolean literal of dialect d if it is true or false, ignoring case.
+// SQLite and MySQL store booleans as integers, so they get 1 and 0. Other values are returned unchanged.
+func boolLiteral(value string, d sqlcup.Dialect) string {
+	literals := map[string]string{"true": "1", "false": "0"}
+	if d == sqlcup.DialectPostgres {
+		literals = map[string]string{"true": "TRUE", "false": "FALSE"}
 	}
-	if len(parts) == 3 {
-		col.Constraint = parts[2]
+	if literal, ok := literals[strings.ToLower(value)]; ok {
+		return literal
 	}
-	return col, nil
+	return value
 }
 
-func parseScaffoldCommandArgs(args []string) (*scaffoldCommandArgs, error) {
-	if len(args) == 0 {
-		return nil, fmt.Errorf("%w: missing <name> and <column>", errBadArgument)
-	}
+// referentialActions maps the <action> of @on-delete and @on-update to SQL.
+var referentialActions = map[string]string{
+	"cascade":   "CASCADE",
+	"set-null":  "SET NULL",
+	"restrict":  "RESTRICT",
+	"no-action": "NO ACTION",
+}
 
-	tableParts := strings.Split(args[0], "/")
+// smartColumnTags contains the keys of all tags known to parseSmartColumnDefinition.
+var smartColumnTags = map[string]bool{
+	"id": true, "null": true, "unique": true, "default": true, "references": true, "check": true,
+	"text": true, "int": true, "bigint": true, "float": true, "double": true, "datetime": true,
+	"blob": true, "bool": true, "varchar": true, "decimal": true, "uuid": true, "smallint": true,
+	"json": true, "jsonb": true, "autoincrement": true, "generated": true, "virtual": true,
+	"enum": true, "comment": true, "tz": true,
+	"readonly": true, "fk": true, "on-delete": true, "on-update": true,
+}
+
+// splitSmartColumnTags splits the tags of a <smart-column>.
+// Because the argument of a @check=<expr>, @generated=<expr> or @comment=<text> tag may contain the separator itself,
+// it extends up to the next known tag.
+func splitSmartColumnTags(s string) []string {
+	var tags []string
+	for _, part := range strings.Split(s, smartColumnSep) {
+		if last := len(tags) - 1; last >= 0 && hasFreeFormArgument(tags[last]) {
+			if key, _, _ := strings.Cut(part, "="); !smartColumnTags[key] {
+				tags[len(tags)-1] += smartColumnSep + part
+				continue
+			}
+		}
+		tags = append(tags, part)
+	}
+	return tags
+}
+
+// hasFreeFormArgument reports whether tag is a tag whose argument may contain the smart column separator.
+func hasFreeFormArgument(tag string) bool {
+	for _, prefix := range []string{"check=", "generated=", "comment="} {
+		if strings.HasPrefix(tag, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePlainColumnDefinition(s string) (sqlcup.Column, error) {
+	// Only split on the first two separators so that <constraint> may contain colons, e.g. DEFAULT '00:00'.
+	parts := strings.SplitN(s, plainColumnSep, 3)
+	if len(parts) < 2 || parts[0] == "" || strings.TrimSuffix(parts[1], "?") == "" {
+		return sqlcup.Column{}, fmt.Errorf("%w: invalid <plain-column>: '%s', expected '<name>:<type>[?][:<constraint>]'", errBadArgument, s)
+	}
+	col := sqlcup.Column{
+		ID:   strings.ToLower(parts[0]) == *idColumnFlag,
+		Name: parts[0],
+		Type: strings.TrimSuffix(parts[1], "?"),
+	}
+	// A trailing '?' marks the column as nullable.
+	nullable := strings.HasSuffix(parts[1], "?")
+	if len(parts) == 3 {
+		col.Constraint = parts[2]
+		col.Unique = strings.Contains(strings.ToUpper(col.Constraint), "UNIQUE")
+	}
+	constraint := strings.ToUpper(col.Constraint)
+	if nullable && strings.Contains(constraint, "NOT NULL") {
+		return sqlcup.Column{}, fmt.Errorf("%w: invalid <plain-column>: '%s', <type>? marks the column nullable, but <constraint> contains NOT NULL", errBadArgument, s)
+	}
+	// With -plain-not-null-default, plain columns are NOT NULL unless marked nullable, just like smart columns.
+	if *plainNotNullDefaultFlag && !nullable && !strings.Contains(constraint, "NOT NULL") && !strings.Contains(constraint, "PRIMARY KEY") {
+		col.Constraint = strings.TrimSpace("NOT NULL " + col.Constraint)
+	}
+	return col, nil
+}
+
+// tableSeparator separates the arguments of multiple tables.
+const tableSeparator = "--"
+
+// parseScaffoldCommandArgs parses the arguments of all tables separated by tableSeparator.
+func parseScaffoldCommandArgs(args []string) ([]*scaffoldCommandArgs, error) {
+	var groups [][]string
+	start := 0
+	for i, arg := range args {
+		if arg == tableSeparator {
+			groups = append(groups, args[start:i])
+			start = i + 1
+		}
+	}
+	// A trailing separator does not start another table.
+	if start < len(args) || len(groups) == 0 {
+		groups = append(groups, args[start:])
+	}
+
+	var tables []*scaffoldCommandArgs
+	for _, group := range groups {
+		// Column definitions can only be read from stdin for a single table.
+		sca, err := parseTableArgs(group, len(groups) == 1)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, sca)
+	}
+	return tables, nil
+}
+
+// parseTableArgs parses the <entity-name> and <column> arguments of a single table.
+// If stdin is true and args contains no <column>, column definitions are read from os.Stdin.
+func parseTableArgs(args []string, stdin bool) (*scaffoldCommandArgs, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: missing <name> and <column>", errBadArgument)
+	}
+
+	tableParts := strings.Split(args[0], "/")
+	if len(tableParts) == 1 {
+		// A single plural name like 'users' is a shortcut for 'user/users'.
+		singular, ok := singularize(tableParts[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid <name>: '%s', cannot derive singular, expected '<singular>/<plural>'", errBadArgument, args[0])
+		}
+		tableParts = []string{singular, tableParts[0]}
+	}
 	if len(tableParts) != 2 || len(tableParts[0]) == 0 || len(tableParts[1]) == 0 {
-		return nil, fmt.Errorf("%w: invalid <name>: '%s', expected '<singular>/<plural>'", errBadArgument, tableParts)
+		return nil, fmt.Errorf("%w: invalid <name>: '%s', expected '<singular>/<plural>' or '<plural>'", errBadArgument, tableParts)
+	}
+	table := tableParts[1]
+	if *lowerSnakeTableFlag {
+		table = snakeCase(table)
+	}
+	sca, err := newTableArgs(table, tableParts[0], tableParts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	defs := args[1:]
+	if len(defs) == 0 && stdin && stdinIsPipe() {
+		defs, err = readColumnDefinitions(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(defs) == 0 {
+		return nil, fmt.Errorf("%w: at least one column required", errBadArgument)
+	}
+
+	var (
+		cols []sqlcup.Column
+		// plain and smart contain the quoted names of the columns by style for -strict-columns.
+		plain, smart []string
+	)
+	for _, arg := range defs {
+		col, err := parseColumnDefinition(arg, sca.Dialect, sca.LowercaseKeywords)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(arg, plainColumnSep) {
+			plain = append(plain, "'"+col.Name+"'")
+		} else {
+			smart = append(smart, "'"+col.Name+"'")
+		}
+		cols = append(cols, col)
+	}
+	if len(plain) > 0 && len(smart) > 0 {
+		// Smart columns are NOT NULL by default, plain columns are not, which is easily overlooked.
+		mixed := fmt.Sprintf("mixed column styles: plain columns %s, smart columns %s", strings.Join(plain, ", "), strings.Join(smart, ", "))
+		if *strictColumnsFlag {
+			return nil, fmt.Errorf("%w: '-strict-columns', %s", errBadArgument, mixed)
+		}
+		//goland:noinspection GoUnhandledErrorResult
+		fmt.Fprintf(os.Stderr, "%s: warning: %s\n", os.Args[0], mixed)
+	}
+	if err := setColumns(sca, cols); err != nil {
+		return nil, err
+	}
+	return sca, nil
+}
+
+// parseStdinJSON parses the table spec for -stdin-json from os.Stdin, which replaces all arguments.
+func parseStdinJSON(args []string) ([]*scaffoldCommandArgs, error) {
+	if len(args) > 0 {
+		return nil, fmt.Errorf("%w: cannot combine '-stdin-json' with <entity-name> and <column> arguments", errBadArgument)
+	}
+	sca, err := parseTableSpec(os.Stdin)
+	if err != nil {
+		return nil, err
+	}
+	return []*scaffoldCommandArgs{sca}, nil
+}
+
+// tableSpec is the table read by -stdin-json. The keys of a column are the field names of sqlcup.Column.
+type tableSpec struct {
+	Table    string          `json:"table"`
+	Singular string          `json:"singular"`
+	Plural   string          `json:"plural"`
+	Columns  []sqlcup.Column `json:"columns"`
+}
+
+// parseTableSpec reads a single table as a JSON tableSpec from r instead of <entity-name> and <column> arguments.
+// The table name defaults to the plural entity name, which in turn defaults to the table name.
+// The singular entity name is derived from the plural unless set.
+func parseTableSpec(r io.Reader) (*scaffoldCommandArgs, error) {
+	var spec tableSpec
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("%w: '-stdin-json', %v", errBadArgument, err)
+	}
+	if spec.Plural == "" {
+		spec.Plural = spec.Table
+	}
+	if spec.Table == "" && *lowerSnakeTableFlag {
+		spec.Table = snakeCase(spec.Plural)
+	} else if spec.Table == "" {
+		spec.Table = spec.Plural
+	}
+	if spec.Plural == "" {
+		return nil, fmt.Errorf("%w: '-stdin-json', missing \"table\" or \"plural\"", errBadArgument)
+	}
+	if spec.Singular == "" {
+		singular, ok := singularize(spec.Plural)
+		if !ok {
+			return nil, fmt.Errorf("%w: '-stdin-json', cannot derive singular of '%s', expected \"singular\"", errBadArgument, spec.Plural)
+		}
+		spec.Singular = singular
+	}
+	sca, err := newTableArgs(spec.Table, spec.Singular, spec.Plural)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec.Columns) == 0 {
+		return nil, fmt.Errorf("%w: at least one column required", errBadArgument)
+	}
+	if err := setColumns(sca, spec.Columns); err != nil {
+		return nil, err
+	}
+	return sca, nil
+}
+
+// newTableArgs returns the arguments of a table without columns as set by the flags.
+func newTableArgs(table, singular, plural string) (*scaffoldCommandArgs, error) {
+	var acronyms []string
+	for _, acronym := range strings.Split(*acronymsFlag, ",") {
+		if acronym = strings.TrimSpace(acronym); acronym != "" {
+			acronyms = append(acronyms, acronym)
+		}
+	}
+	var entityCase func(string) string
+	switch *entityCaseFlag {
+	case "upper-camel":
+		entityCase = func(s string) string { return sqlcup.UpperCamelCaseAcronyms(s, acronyms) }
+	case "snake":
+		entityCase = snakeCase
+	case "raw":
+		entityCase = func(s string) string { return s }
+	default:
+		return nil, fmt.Errorf("%w: '-entity-case %s', expected 'upper-camel', 'snake' or 'raw'", errBadArgument, *entityCaseFlag)
+	}
+
+	if *entityCaseFlag == "upper-camel" {
+		// The entity names start the names of the generated Go methods, e.g. AuthorExists.
+		for _, name := range []string{singular, plural} {
+			if r := []rune(entityCase(name)); len(r) == 0 || !unicode.IsLetter(r[0]) {
+				return nil, fmt.Errorf("%w: invalid <name>: '%s', expected a name that starts with a letter", errBadArgument, name)
+			}
+		}
 	}
 
 	sca := &scaffoldCommandArgs{
-		Table:             tableParts[1],
-		SingularEntity:    upperCamelCase(tableParts[0]),
-		PluralEntity:      upperCamelCase(tableParts[1]),
-		NoExistsClause:    *noExistsClauseFlag,
-		NoReturningClause: *noReturningClauseFlag,
-		OrderBy:           *orderByFlag,
-	}
-	switch *onlyFlag {
+		Args: sqlcup.Args{
+			Table:             *tablePrefixFlag + table,
+			Acronyms:          acronyms,
+			TableComment:      *tableCommentFlag,
+			SchemaName:        *schemaNameFlag,
+			SingularEntity:    entityCase(singular),
+			PluralEntity:      entityCase(plural),
+			NoExistsClause:    *noExistsClauseFlag,
+			WithDrop:          *withDropFlag,
+			View:              *viewFlag,
+			NoReturningClause: *noReturningClauseFlag,
+			NoCreateReturning: *noCreateReturningFlag,
+			NoCount:           *noCountFlag,
+			Paginate:          *paginateFlag,
+			Keyset:            *keysetFlag,
+			NamedParams:       *namedParamsFlag,
+			Timestamps:        *timestampsFlag,
+			SoftDelete:        *softDeleteFlag,
+			PartialUpdates:    *partialUpdatesFlag,
+			CoalesceUpdate:    *coalesceUpdateFlag,
+			BatchInsert:       *batchInsertFlag,
+			WithTruncate:      *withTruncateFlag,
+			ForUpdate:         *forUpdateFlag,
+			RowidGet:          *withRowidGetFlag,
+			GetManyByID:       *getManyByIDFlag,
+			Upsert:            *upsertFlag,
+			UniqueAsIndex:     *uniqueAsIndexFlag,
+			NullableHints:     *nullAsPointerHintFlag,
+			ExplicitColumns:   *explicitColumnsFlag,
+			QuoteIdentifiers:  *quoteIdentifiersFlag,
+			AlignConstraints:  *alignConstraintsFlag,
+			MaxLineWidth:      *maxLineWidthFlag,
+		},
+		Desc:       *descFlag,
+		Fixtures:   *testFixturesFlag,
+		SchemaOut:  *schemaOutFlag,
+		QueriesOut: *queriesOutFlag,
+		Append:     *appendFlag,
+	}
+	var err error
+	for _, name := range []struct {
+		flag     string
+		template string
+		dst      *string
+	}{
+		{"-get-name", *getNameFlag, &sca.Names.Get},
+		{"-list-name", *listNameFlag, &sca.Names.List},
+		{"-create-name", *createNameFlag, &sca.Names.Create},
+		{"-delete-name", *deleteNameFlag, &sca.Names.Delete},
+		{"-update-name", *updateNameFlag, &sca.Names.Update},
+	} {
+		*name.dst, err = executeNameTemplate(name.template, sca)
+		if err != nil {
+			return nil, fmt.Errorf("%w: '%s %s', %v", errBadArgument, name.flag, name.template, err)
+		}
+	}
+
+	for _, omit := range []struct {
+		set   bool
+		query sqlcup.BasicQuery
+	}{
+		{*noGetFlag, sqlcup.QueryGet},
+		{*noListFlag, sqlcup.QueryList},
+		{*noCreateFlag, sqlcup.QueryCreate},
+		{*noUpdateFlag, sqlcup.QueryUpdate},
+		{*noDeleteFlag, sqlcup.QueryDelete},
+	} {
+		if omit.set {
+			sca.OmitQueries = sca.OmitQueries | omit.query
+		}
+	}
+
+	only := *onlyFlag
+	if *schemaOnlyFlag && *queriesOnlyFlag {
+		return nil, fmt.Errorf("%w: cannot combine '-schema-only' and '-queries-only'", errBadArgument)
+	}
+	for _, alias := range []struct {
+		set   bool
+		name  string
+		value string
+	}{
+		{*schemaOnlyFlag, "-schema-only", "schema"},
+		{*queriesOnlyFlag, "-queries-only", "queries"},
+	} {
+		if !alias.set {
+			continue
+		}
+		if only != "" && only != alias.value {
+			return nil, fmt.Errorf("%w: cannot combine '%s' and '-only %s'", errBadArgument, alias.name, only)
+		}
+		only = alias.value
+	}
+	if sca.View {
+		// A view has no schema to generate.
+		if only == "schema" {
+			return nil, fmt.Errorf("%w: cannot combine '-view' and '-only schema'", errBadArgument)
+		}
+		only = "queries"
+	}
+	switch only {
 	case "schema":
 		sca.Output = sca.Output | outputSchema
 	case "queries":
@@ -273,194 +1496,661 @@ func parseScaffoldCommandArgs(args []string) (*scaffoldCommandArgs, error) {
 	case "":
 		sca.Output = sca.Output | outputAll
 	default:
-		return nil, fmt.Errorf("%w: '-only %s', expected 'schema' or 'queries'", errBadArgument, *onlyFlag)
+		return nil, fmt.Errorf("%w: '-only %s', expected 'schema' or 'queries'", errBadArgument, only)
+	}
+	if *outputDirFlag != "" {
+		if sca.SchemaOut != "" || sca.QueriesOut != "" {
+			return nil, fmt.Errorf("%w: cannot combine '-output-dir' with '-schema-out' or '-queries-out'", errBadArgument)
+		}
+		sca.SchemaOut = filepath.Join(*outputDirFlag, "schema.sql")
+		sca.QueriesOut = filepath.Join(*outputDirFlag, "query", sca.Table+".sql")
+	}
+	switch *formatFlag {
+	case "text":
+		sca.Format = formatText
+	case "json":
+		sca.Format = formatJSON
+		if sca.SchemaOut != "" || sca.QueriesOut != "" {
+			return nil, fmt.Errorf("%w: cannot combine '-format json' with '-schema-out' or '-queries-out'", errBadArgument)
+		}
+	default:
+		return nil, fmt.Errorf("%w: '-format %s', expected 'text' or 'json'", errBadArgument, *formatFlag)
+	}
+	if sca.Append && sca.QueriesOut == "" {
+		return nil, fmt.Errorf("%w: '-append' requires '-queries-out'", errBadArgument)
+	}
+	switch *dialectFlag {
+	case "sqlite":
+		sca.Dialect = sqlcup.DialectSQLite
+	case "postgres":
+		sca.Dialect = sqlcup.DialectPostgres
+	case "mysql":
+		sca.Dialect = sqlcup.DialectMySQL
+	default:
+		return nil, fmt.Errorf("%w: '-dialect %s', expected 'sqlite', 'postgres' or 'mysql'", errBadArgument, *dialectFlag)
+	}
+	switch *indentFlag {
+	case "2":
+		sca.Indent = "  "
+	case "4":
+		sca.Indent = "    "
+	case "tab":
+		sca.Indent = "\t"
+	default:
+		return nil, fmt.Errorf("%w: '-indent %s', expected '2', '4' or 'tab'", errBadArgument, *indentFlag)
+	}
+	switch *sectionOrderFlag {
+	case "schema,queries":
+	case "queries,schema":
+		sca.QueriesFirst = true
+	default:
+		return nil, fmt.Errorf("%w: '-section-order %s', expected 'schema,queries' or 'queries,schema'", errBadArgument, *sectionOrderFlag)
+	}
+	if sca.Fixtures < 0 {
+		return nil, fmt.Errorf("%w: '-test-fixtures %d', expected a positive number of rows or 0", errBadArgument, sca.Fixtures)
+	}
+	if *maxLineWidthFlag < 0 {
+		return nil, fmt.Errorf("%w: '-max-line-width %d', expected a positive width or 0", errBadArgument, *maxLineWidthFlag)
+	}
+	switch *keywordCaseFlag {
+	case "upper":
+	case "lower":
+		sca.LowercaseKeywords = true
+	default:
+		return nil, fmt.Errorf("%w: '-keyword-case %s', expected 'upper' or 'lower'", errBadArgument, *keywordCaseFlag)
+	}
+	if sca.RowidGet && sca.Dialect != sqlcup.DialectSQLite {
+		return nil, fmt.Errorf("%w: '-with-rowid-get' requires '-dialect sqlite', only SQLite tables have a rowid", errBadArgument)
+	}
+	if sca.ForUpdate && sca.Dialect == sqlcup.DialectSQLite {
+		return nil, fmt.Errorf("%w: cannot combine '-for-update' with '-dialect sqlite', SQLite has no row locks", errBadArgument)
+	}
+	switch *placeholderStyleFlag {
+	case "question":
+		sca.PlaceholderStyle = sqlcup.PlaceholderQuestion
+	case "dollar":
+		sca.PlaceholderStyle = sqlcup.PlaceholderDollar
+	case "":
+		sca.PlaceholderStyle = sqlcup.PlaceholderDefault
+	default:
+		return nil, fmt.Errorf("%w: '-placeholder-style %s', expected 'question' or 'dollar'", errBadArgument, *placeholderStyleFlag)
 	}
 
-	for _, arg := range args[1:] {
-		col, err := parseColumnDefinition(arg)
+	if *sqliteVersionFlag != "" {
+		if sca.Dialect != sqlcup.DialectSQLite {
+			return nil, fmt.Errorf("%w: '-sqlite-version' requires '-dialect sqlite'", errBadArgument)
+		}
+		returning, err := sqliteSupportsReturning(*sqliteVersionFlag)
 		if err != nil {
 			return nil, err
 		}
-		if len(col.Name) > sca.LongestName {
-			sca.LongestName = len(col.Name)
-		}
-		if len(col.Type) > sca.LongestType {
-			sca.LongestType = len(col.Type)
+		if !returning {
+			if *returningFlag != "" {
+				return nil, fmt.Errorf("%w: cannot combine '-returning' and '-sqlite-version %s', RETURNING requires SQLite 3.35", errBadArgument, *sqliteVersionFlag)
+			}
+			sca.NoReturningClause = true
+			sca.NoCreateReturning = true
+			//goland:noinspection GoUnhandledErrorResult
+			fmt.Fprintf(os.Stderr, "%s: note: omitting RETURNING clauses, SQLite %s does not support them\n", os.Args[0], *sqliteVersionFlag)
 		}
-		sca.Columns = append(sca.Columns, col)
-		if col.ID {
-			sca.IDColumn = &col
-		} else {
-			sca.NonIDColumns = append(sca.NonIDColumns, col)
+	}
+
+	// MySQL does not support RETURNING, but :execresult gives access to the id of the inserted row.
+	createFallback := "execresult"
+	if sca.NoCreateReturning {
+		createFallback = "exec"
+	}
+	sca.CreateKind, err = parseQueryKind("-create-kind", *createKindFlag, !sca.NoCreateReturning && sca.Dialect != sqlcup.DialectMySQL, createFallback)
+	if err != nil {
+		return nil, err
+	}
+	sca.UpdateKind, err = parseQueryKind("-update-kind", *updateKindFlag, !sca.NoReturningClause && sca.Dialect != sqlcup.DialectMySQL, "exec")
+	if err != nil {
+		return nil, err
+	}
+
+	sca.Table, err = checkIdentifier(sca.Table, sca.Dialect, *allowQuotedFlag)
+	if err != nil {
+		return nil, err
+	}
+	if sca.SchemaName != "" {
+		sca.SchemaName, err = checkIdentifier(sca.SchemaName, sca.Dialect, *allowQuotedFlag)
+		if err != nil {
+			return nil, err
 		}
 	}
+
 	return sca, nil
 }
 
-func scaffoldCommand(args *scaffoldCommandArgs) error {
-	b := &strings.Builder{}
-
-	if args.Output&outputAll == outputAll {
-		b.WriteString("#############################################\n")
-		b.WriteString("# Add the following to your SQL schema file #\n")
-		b.WriteString("#############################################\n\n")
+// setColumns checks the names of cols and sets them as the columns of sca together with the column-dependent flags.
+func setColumns(sca *scaffoldCommandArgs, cols []sqlcup.Column) error {
+	var (
+		err error
+		// seen contains the lowercase names of all columns, like the detection of id columns.
+		seen = make(map[string]bool)
+	)
+	for i := range cols {
+		cols[i].Name, err = checkIdentifier(cols[i].Name, sca.Dialect, *allowQuotedFlag)
+		if err != nil {
+			return err
+		}
+		if seen[strings.ToLower(cols[i].Name)] {
+			return fmt.Errorf("%w: duplicate column '%s'", errBadArgument, cols[i].Name)
+		}
+		seen[strings.ToLower(cols[i].Name)] = true
+		// Columns are nullable unless their constraint says otherwise, like in SQL.
+		if constraint := strings.ToUpper(cols[i].Constraint); !strings.Contains(constraint, "NOT NULL") && !strings.Contains(constraint, "PRIMARY KEY") {
+			cols[i].Nullable = true
+		}
 	}
-	if args.Output&outputSchema != 0 {
-		writeSchema(b, args)
-		b.WriteString("\n\n")
+	noteMissingID(os.Stderr, sca, cols)
+	if *idFirstFlag {
+		// Keep the relative order of id and other columns.
+		sort.SliceStable(cols, func(i, j int) bool {
+			return cols[i].ID && !cols[j].ID
+		})
 	}
-	if args.Output&outputAll == outputAll {
-		b.WriteString("##############################################\n")
-		b.WriteString("# Add the following to your SQL queries file #\n")
-		b.WriteString("##############################################\n\n")
+	if *excludeFromInsertFlag != "" {
+		for _, name := range strings.Split(*excludeFromInsertFlag, ",") {
+			if !seen[strings.ToLower(name)] {
+				return fmt.Errorf("%w: '-exclude-from-insert %s', no such column '%s'", errBadArgument, *excludeFromInsertFlag, name)
+			}
+			for i := range cols {
+				if strings.EqualFold(cols[i].Name, name) {
+					cols[i].ReadOnly = true
+				}
+			}
+		}
 	}
-	if args.Output&outputQueries != 0 {
-		if args.IDColumn != nil {
-			writeGetQuery(b, args)
-			b.WriteString("\n\n")
+	sca.Columns = cols
+	for _, index := range indexFlag {
+		sca.Indexes = append(sca.Indexes, strings.Split(index, ","))
+	}
+	for _, unique := range uniqueFlag {
+		sca.UniqueConstraints = append(sca.UniqueConstraints, strings.Split(unique, ","))
+	}
+	if *filterByFlag != "" {
+		sca.FilterBy = strings.Split(*filterByFlag, ",")
+	}
+	sca.OrderBy, sca.ListOrderBy, err = parseOrderBy(*orderByFlag, sca.FilterBy)
+	if err != nil {
+		return err
+	}
+	if *returningFlag != "" {
+		if sca.NoReturningClause {
+			return fmt.Errorf("%w: cannot combine '-returning' and '-no-returning-clause'", errBadArgument)
 		}
+		sca.Returning = strings.Split(*returningFlag, ",")
+	}
+	if *upsertConflictFlag != "" {
+		sca.ConflictColumns = strings.Split(*upsertConflictFlag, ",")
+	}
+	// Report invalid arguments like unknown -order-by columns before the output of any table is written.
+	return sqlcup.Validate(sca.Args)
+}
 
-		writeListQuery(b, args)
-		b.WriteString("\n\n")
+// sqliteSupportsReturning reports whether SQLite of the given version, like 3.30 or 3.30.1, supports RETURNING.
+// SQLite gained RETURNING in version 3.35.
+func sqliteSupportsReturning(version string) (bool, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return false, fmt.Errorf("%w: '-sqlite-version %s', expected '<major>.<minor>[.<patch>]'", errBadArgument, version)
+	}
+	var nums []int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return false, fmt.Errorf("%w: '-sqlite-version %s', expected '<major>.<minor>[.<patch>]'", errBadArgument, version)
+		}
+		nums = append(nums, n)
+	}
+	return nums[0] > 3 || nums[0] == 3 && nums[1] >= 35, nil
+}
 
-		writeCreateQuery(b, args)
-		b.WriteString("\n")
+// parseOrderBy parses the value of -order-by into the default ORDER BY terms and the terms of single list queries.
+// Entries of the form list=<terms> and listBy<Column>=<terms> are separated by semicolons;
+// an entry without a query name sets the default for all other list queries.
+func parseOrderBy(s string, filterBy []string) (string, map[string]string, error) {
+	if !strings.ContainsAny(s, "=;") {
+		return s, nil, nil
+	}
+	var (
+		def      string
+		perQuery = make(map[string]string)
+	)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, terms, ok := strings.Cut(entry, "=")
+		if !ok {
+			if def != "" {
+				return "", nil, fmt.Errorf("%w: '-order-by %s', more than one default", errBadArgument, s)
+			}
+			def = entry
+			continue
+		}
+		name, terms = strings.TrimSpace(name), strings.TrimSpace(terms)
+		filter, found := "", strings.EqualFold(name, "list")
+		for _, col := range filterBy {
+			if strings.EqualFold(name, "listBy"+sqlcup.UpperCamelCase(col)) {
+				filter, found = col, true
+			}
+		}
+		if !found {
+			return "", nil, fmt.Errorf("%w: '-order-by %s', unknown query '%s', expected 'list' or 'listBy<Column>' of a '-filter-by' column", errBadArgument, s, name)
+		}
+		perQuery[filter] = terms
+	}
+	return def, perQuery, nil
+}
 
-		if args.IDColumn != nil {
-			b.WriteString("\n")
-			writeDeleteQuery(b, args)
-			b.WriteString("\n\n")
-			writeUpdateQuery(b, args)
-			b.WriteString("\n\n")
+// singularize derives the singular of the plural noun s by stripping a trailing 's' or replacing 'ies' with 'y'.
+// It reports false if s does not look like a plural.
+func singularize(s string) (string, bool) {
+	switch {
+	case len(s) > 3 && strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y", true
+	case len(s) > 1 && strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return strings.TrimSuffix(s, "s"), true
+	}
+	return "", false
+}
+
+// snakeCase converts a string like "ZipcodeImports" or "zipcode-imports" to "zipcode_imports".
+func snakeCase(s string) string {
+	b := strings.Builder{}
+	r := []rune(s)
+	for i, c := range r {
+		switch {
+		case c == '-':
+			b.WriteRune('_')
+		case unicode.IsUpper(c):
+			// Start a new word after a lowercase letter or digit, or at the last capital of an acronym like "HTTPLogs".
+			if i > 0 && (unicode.IsLower(r[i-1]) || unicode.IsDigit(r[i-1]) ||
+				unicode.IsUpper(r[i-1]) && i+1 < len(r) && unicode.IsLower(r[i+1])) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(c))
+		default:
+			b.WriteRune(c)
 		}
 	}
-	fmt.Print(b)
-	return nil
+	return b.String()
 }
 
-//goland:noinspection GoUnhandledErrorResult
-func writeSchema(w io.Writer, args *scaffoldCommandArgs) {
-	fmt.Fprint(w, "CREATE TABLE ")
-	if !args.NoExistsClause {
-		fmt.Fprint(w, "IF NOT EXISTS ")
+// identifierPattern matches unquoted SQL identifiers.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedWords contains SQL keywords that cannot be used as unquoted identifiers in any dialect.
+var reservedWords = map[string]bool{
+	"ALL": true, "AND": true, "AS": true, "BY": true, "CASE": true, "CHECK": true, "COLUMN": true,
+	"CONSTRAINT": true, "CREATE": true, "DEFAULT": true, "DELETE": true, "DISTINCT": true, "DROP": true,
+	"ELSE": true, "FOREIGN": true, "FROM": true, "GROUP": true, "HAVING": true, "IN": true, "INDEX": true,
+	"INSERT": true, "INTO": true, "IS": true, "JOIN": true, "KEY": true, "LIMIT": true, "NOT": true,
+	"NULL": true, "ON": true, "OR": true, "ORDER": true, "PRIMARY": true, "REFERENCES": true,
+	"SELECT": true, "SET": true, "TABLE": true, "THEN": true, "UNION": true, "UNIQUE": true,
+	"UPDATE": true, "VALUES": true, "WHEN": true, "WHERE": true,
+}
+
+// checkIdentifier returns name if it is a valid SQL identifier or already quoted.
+// Otherwise, it returns name quoted for dialect d if allowQuoted is true, or an error wrapping errBadArgument.
+func checkIdentifier(name string, d sqlcup.Dialect, allowQuoted bool) (string, error) {
+	if sqlcup.IsQuotedIdentifier(name) {
+		return name, nil
+	}
+	if identifierPattern.MatchString(name) && !reservedWords[strings.ToUpper(name)] {
+		return name, nil
 	}
-	fmt.Fprint(w, args.Table)
-	fmt.Fprint(w, " (\n")
+	if allowQuoted {
+		return sqlcup.QuoteIdentifier(name, d), nil
+	}
+	return "", fmt.Errorf("%w: invalid identifier '%s', expected [A-Za-z_][A-Za-z0-9_]* and no reserved word, or use '-allow-quoted'", errBadArgument, name)
+}
 
-	for ci, col := range args.Columns {
-		fmt.Fprintf(w, "  %s ", col.Name)
-		no := args.LongestName - len(col.Name)
-		for i := 0; i < no; i++ {
-			fmt.Fprintf(w, " ")
+// stdinIsPipe reports whether os.Stdin is connected to a pipe or file rather than a terminal.
+func stdinIsPipe() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice == 0
+}
+
+// readColumnDefinitions reads one <column> per line from r.
+// Blank lines and lines starting with # are skipped.
+func readColumnDefinitions(r io.Reader) ([]string, error) {
+	var defs []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
 		}
-		fmt.Fprintf(w, "%s", col.Type)
-		if col.Constraint != "" {
-			to := args.LongestType - len(col.Type)
-			for i := 0; i < to; i++ {
-				fmt.Fprintf(w, " ")
-			}
-			fmt.Fprintf(w, " %s", col.Constraint)
+		defs = append(defs, line)
+	}
+	return defs, sc.Err()
+}
+
+// parseQueryKind validates the sqlc query kind given to flag and returns it without the leading colon.
+// An empty kind defaults to 'one' if the statement returns the affected row and to fallback otherwise.
+func parseQueryKind(flag, kind string, returning bool, fallback string) (string, error) {
+	switch kind {
+	case "":
+		if returning {
+			return "one", nil
 		}
-		if ci < len(args.Columns)-1 {
-			fmt.Fprintf(w, ",")
+		return fallback, nil
+	case "one":
+		if !returning {
+			return "", fmt.Errorf("%w: '%s one' requires a RETURNING clause", errBadArgument, flag)
 		}
-		fmt.Fprintf(w, "\n")
+	case "execresult":
+		if returning {
+			return "", fmt.Errorf("%w: '%s execresult' conflicts with the RETURNING clause", errBadArgument, flag)
+		}
+	case "exec", "execrows":
+	default:
+		return "", fmt.Errorf("%w: '%s %s', expected 'one', 'exec', 'execresult' or 'execrows'", errBadArgument, flag, kind)
 	}
-	fmt.Fprintf(w, ");")
+	return kind, nil
 }
 
-//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
-func writeGetQuery(w io.Writer, args *scaffoldCommandArgs) {
-	fmt.Fprintf(w, "-- name: Get%s :one\n", args.SingularEntity)
-	fmt.Fprintf(w, "SELECT * FROM %s\n", args.Table)
-	fmt.Fprintf(w, "WHERE %s = ? LIMIT 1;", args.IDColumn.Name)
+// executeNameTemplate renders the query name template tmpl with the entity names of args.
+func executeNameTemplate(tmpl string, args *scaffoldCommandArgs) (string, error) {
+	t, err := template.New("name").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	b := &strings.Builder{}
+	err = t.Execute(b, struct{ Singular, Plural string }{args.SingularEntity, args.PluralEntity})
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(b.String()) == "" {
+		return "", errors.New("empty query name")
+	}
+	return b.String(), nil
 }
 
-//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
-func writeListQuery(w io.Writer, args *scaffoldCommandArgs) {
-	fmt.Fprintf(w, "-- name: List%s :many\n", args.PluralEntity)
-	fmt.Fprintf(w, "SELECT * FROM %s", args.Table)
-	if args.OrderBy == "" {
-		fmt.Fprintf(w, ";")
-	} else {
-		fmt.Fprintf(w, "\nORDER BY %s;", args.OrderBy)
+// scaffoldCommand writes the output of all tables on stdout to w, separated by an empty line.
+func scaffoldCommand(w io.Writer, tables []*scaffoldCommandArgs) error {
+	var outputs []string
+	for _, args := range tables {
+		out, err := scaffoldTable(args, len(tables) > 1)
+		if err != nil {
+			return err
+		}
+		if out = strings.TrimRight(out, "\n"); out != "" {
+			outputs = append(outputs, out)
+		}
+	}
+	if len(outputs) == 0 {
+		return nil
+	}
+	if len(outputs) > 1 && tables[0].Format == formatJSON {
+		return writeJSONArray(w, outputs)
 	}
+	// The output on stdout ends with exactly one newline, so that it can be appended to files as is.
+	out := strings.Join(outputs, "\n\n")
+	if !*noTrailingNewlineFlag {
+		out += "\n"
+	}
+	_, err := io.WriteString(w, out)
+	return err
 }
 
-//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
-func writeCreateQuery(w io.Writer, args *scaffoldCommandArgs) {
-	fmt.Fprintf(w, "-- name: Create%s :one\n", args.SingularEntity)
-	fmt.Fprintf(w, "INSERT INTO %s (\n", args.Table)
-	fmt.Fprintf(w, "  ")
-	for i, col := range args.NonIDColumns {
-		fmt.Fprint(w, col.Name)
-		if i == len(args.NonIDColumns)-1 {
-			fmt.Fprintf(w, "\n")
-		} else {
-			fmt.Fprintf(w, ", ")
+// scaffoldTable writes the schema and queries of a single table and returns the output for stdout.
+// If title is true, the output on stdout starts with a banner naming the table.
+func scaffoldTable(args *scaffoldCommandArgs, title bool) (string, error) {
+	var schema string
+	if args.Output&outputSchema != 0 {
+		var err error
+		schema, err = sqlcup.GenerateSchema(args.Args)
+		if err != nil {
+			return "", err
 		}
 	}
-	fmt.Fprintf(w, ") VALUES (\n")
-	fmt.Fprint(w, "  ")
-	for i := 0; i < len(args.NonIDColumns); i++ {
-		if i < len(args.NonIDColumns)-1 {
-			fmt.Fprint(w, "?, ")
-		} else {
-			fmt.Fprint(w, "?\n")
+
+	queries := []sqlcup.Query{}
+	if args.Output&outputQueries != 0 {
+		var skip map[string]bool
+		if args.Append {
+			var err error
+			skip, err = readQueryNames(args.QueriesOut)
+			if err != nil {
+				return "", err
+			}
+		}
+		generated, err := sqlcup.GenerateQueries(args.Args)
+		if err != nil {
+			return "", err
+		}
+		for _, q := range generated {
+			if skip[q.Name] {
+				//goland:noinspection GoUnhandledErrorResult
+				fmt.Fprintf(os.Stderr, "%s: skipping query %s, already defined in %s\n", os.Args[0], q.Name, args.QueriesOut)
+				continue
+			}
+			queries = append(queries, q)
+		}
+	}
+
+	var fixtures string
+	if args.Fixtures > 0 {
+		var err error
+		fixtures, err = sqlcup.GenerateFixtures(args.Args, args.Fixtures)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if args.Format == formatJSON {
+		b := &strings.Builder{}
+		err := writeJSON(b, schema, queries, fixtures)
+		return b.String(), err
+	}
+
+	var texts []string
+	for _, q := range queries {
+		texts = append(texts, q.Text)
+	}
+	if args.Desc != "" {
+		if schema != "" {
+			schema = sqlComment(args.Desc) + "\n" + schema
+		}
+		if len(texts) > 0 {
+			texts[0] = sqlComment(args.Desc) + "\n" + texts[0]
+		}
+	}
+	if *stampFlag && len(texts) > 0 {
+		texts[0] = stamp() + "\n" + texts[0]
+	}
+
+	// Banners are only needed to tell both sections apart when they are printed together.
+	banners := args.Output&outputAll == outputAll && args.SchemaOut == "" && args.QueriesOut == "" && !*noBannersFlag
+
+	b := &strings.Builder{}
+	stdout := args.Output&outputSchema != 0 && args.SchemaOut == "" || args.Output&outputQueries != 0 && args.QueriesOut == "" || fixtures != ""
+	if title && stdout && !*noBannersFlag {
+		writeBanner(b, "Table "+args.Table)
+	}
+	writeSchemaSection := func() error {
+		if args.Output&outputSchema == 0 {
+			return nil
+		}
+		if args.SchemaOut != "" {
+			return appendSection(args.SchemaOut, schema)
+		}
+		if banners {
+			writeBanner(b, "Add the following to your SQL schema file")
+		}
+		b.WriteString(schema)
+		b.WriteString("\n\n")
+		return nil
+	}
+	writeQueriesSection := func() error {
+		if args.Output&outputQueries == 0 {
+			return nil
+		}
+		if args.QueriesOut != "" {
+			if len(texts) == 0 {
+				return nil
+			}
+			return appendSection(args.QueriesOut, strings.Join(texts, "\n\n"))
+		}
+		if banners {
+			writeBanner(b, "Add the following to your SQL queries file")
+		}
+		b.WriteString(strings.Join(texts, "\n\n"))
+		b.WriteString("\n\n")
+		return nil
+	}
+	sections := []func() error{writeSchemaSection, writeQueriesSection}
+	if args.QueriesFirst {
+		sections = []func() error{writeQueriesSection, writeSchemaSection}
+	}
+	for _, writeSection := range sections {
+		if err := writeSection(); err != nil {
+			return "", err
 		}
 	}
-	fmt.Fprintf(w, ")\n")
-	fmt.Fprintf(w, "RETURNING *;")
+	if fixtures != "" {
+		// Sample rows belong to neither file, so they are always printed.
+		if !*noBannersFlag {
+			writeBanner(b, "Run the following to insert sample rows")
+		}
+		b.WriteString(fixtures)
+		b.WriteString("\n\n")
+	}
+	return b.String(), nil
 }
 
-//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
-func writeDeleteQuery(w io.Writer, args *scaffoldCommandArgs) {
-	fmt.Fprintf(w, "-- name: Delete%s :exec\n", args.SingularEntity)
-	fmt.Fprintf(w, "DELETE FROM %s\n", args.Table)
-	fmt.Fprintf(w, "WHERE %s = ?;", args.IDColumn.Name)
+// sqlComment returns text as an SQL comment, starting each line with '-- '.
+func sqlComment(text string) string {
+	return "-- " + strings.ReplaceAll(text, "\n", "\n-- ")
 }
 
-//goland:noinspection GoUnhandledErrorResult
-func writeUpdateQuery(w io.Writer, args *scaffoldCommandArgs) {
-	var mode string
-	if args.NoReturningClause {
-		mode = ":exec"
-	} else {
-		mode = ":one"
-	}
-	fmt.Fprintf(w, "-- name: Update%s %s\n", args.SingularEntity, mode)
-	fmt.Fprintf(w, "UPDATE %s\n", args.Table)
-	fmt.Fprintf(w, "SET\n")
-	for i, col := range args.NonIDColumns {
-		if i < len(args.NonIDColumns)-1 {
-			fmt.Fprintf(w, "  %s = ?,\n", col.Name)
-		} else {
-			fmt.Fprintf(w, "  %s = ?\n", col.Name)
+// stamp returns a comment that names the sqlcup version and the arguments the output was generated from.
+func stamp() string {
+	v := version
+	if v == "" {
+		v = "(devel)"
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+			v = info.Main.Version
 		}
 	}
-	fmt.Fprintf(w, "WHERE %s = ?", args.IDColumn.Name)
-	if !args.NoReturningClause {
-		fmt.Fprintf(w, "\nRETURNING *;")
-	} else {
-		fmt.Fprintf(w, ";")
+	args := []string{"sqlcup"}
+	for _, arg := range os.Args[1:] {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`") {
+			arg = strconv.Quote(arg)
+		}
+		args = append(args, arg)
 	}
+	return fmt.Sprintf("-- generated by sqlcup %s from: %s", v, strings.Join(args, " "))
+}
+
+// writeBanner writes text framed by dashes to b.
+// The banner consists of SQL comments, so the output remains valid SQL.
+func writeBanner(b *strings.Builder, text string) {
+	line := "-- " + text + " --"
+	b.WriteString(strings.Repeat("-", len(line)) + "\n")
+	b.WriteString(line + "\n")
+	b.WriteString(strings.Repeat("-", len(line)) + "\n\n")
 }
 
-// upperCamelCase converts a string like "zipcode_imports" to "ZipcodeImports".
-func upperCamelCase(s string) string {
-	parts := strings.Split(s, "_")
-	if len(parts) == 1 {
-		return capitalize(s)
+// writeJSON writes schema and queries to w as a single JSON object.
+func writeJSON(w io.Writer, schema string, queries []sqlcup.Query, fixtures string) error {
+	type jsonQuery struct {
+		Name string `json:"name"`
+		Kind string `json:"kind"`
+		SQL  string `json:"sql"`
 	}
-	b := strings.Builder{}
-	for _, p := range parts {
-		b.WriteString(capitalize(p))
+	out := struct {
+		Schema   string      `json:"schema"`
+		Queries  []jsonQuery `json:"queries"`
+		Fixtures string      `json:"fixtures,omitempty"`
+	}{
+		Schema:   schema,
+		Queries:  []jsonQuery{},
+		Fixtures: fixtures,
 	}
-	return b.String()
+	for _, q := range queries {
+		_, sql, _ := strings.Cut(q.Text, "\n")
+		out.Queries = append(out.Queries, jsonQuery{Name: q.Name, Kind: q.Kind, SQL: sql})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
 }
 
-func capitalize(s string) string {
-	r := []rune(s)
-	r[0] = unicode.ToUpper(r[0])
-	return string(r)
+// writeJSONArray writes the JSON objects of several tables to w as a single JSON array.
+func writeJSONArray(w io.Writer, objects []string) error {
+	var array []json.RawMessage
+	for _, o := range objects {
+		array = append(array, json.RawMessage(o))
+	}
+	b := &strings.Builder{}
+	enc := json.NewEncoder(b)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(array); err != nil {
+		return err
+	}
+	out := b.String()
+	if *noTrailingNewlineFlag {
+		out = strings.TrimRight(out, "\n")
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// queryNamePattern matches the sqlc annotation that names a query.
+var queryNamePattern = regexp.MustCompile(`(?m)^-- name: (\S+)`)
+
+// readQueryNames returns the names of all annotated queries in the file at path.
+// A missing file contains no queries.
+func readQueryNames(path string) (map[string]bool, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, m := range queryNamePattern.FindAllStringSubmatch(string(content), -1) {
+		names[m[1]] = true
+	}
+	return names, nil
+}
+
+// appendSection appends section to the file at path, creating the file and its parent directories if necessary.
+// If the file is not empty, section is separated from the existing content by an empty line.
+func appendSection(path string, section string) (err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	section = strings.TrimRight(section, "\n") + "\n"
+	if fi.Size() > 0 {
+		section = "\n" + section
+	}
+	if _, err = f.WriteString(section); err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	fmt.Fprintf(os.Stderr, "%s: wrote %s\n", os.Args[0], path)
+	return nil
 }
@@ -1,29 +1,160 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ngrash/sqlcup"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 )
 
-var smartColTests = map[string]struct {
-	col column
+// update regenerates the golden files of TestGolden: go test ./cmd/sqlcup -run TestGolden -update
+var update = flag.Bool("update", false, "Update the golden files in testdata")
+
+type smartColTestCases map[string]struct {
+	col sqlcup.Column
 	err error
-}{
-	"@id":                 {col: column{Name: "id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true}},
-	"col_id@id":           {col: column{Name: "col_id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true}},
-	"primary_key@text@id": {col: column{Name: "primary_key", Type: "TEXT", Constraint: "NOT NULL PRIMARY KEY", ID: true}},
-	"col@text":            {col: column{Name: "col", Type: "TEXT", Constraint: "NOT NULL", ID: false}},
-	"col@text@null":       {col: column{Name: "col", Type: "TEXT", Constraint: "", ID: false}},
-	"col@text@unique":     {col: column{Name: "col", Type: "TEXT", Constraint: "NOT NULL UNIQUE", ID: false}},
-	"col@int":             {col: column{Name: "col", Type: "INTEGER", Constraint: "NOT NULL", ID: false}},
-	"col@datetime":        {col: column{Name: "col", Type: "DATETIME", Constraint: "NOT NULL", ID: false}, err: nil},
+}
+
+var smartColTests = smartColTestCases{
+	"@id":                        {col: sqlcup.Column{Name: "id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true}},
+	"col_id@id":                  {col: sqlcup.Column{Name: "col_id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true}},
+	"primary_key@text@id":        {col: sqlcup.Column{Name: "primary_key", Type: "TEXT", Constraint: "NOT NULL PRIMARY KEY", ID: true}},
+	"col@text":                   {col: sqlcup.Column{Name: "col", Type: "TEXT", Constraint: "NOT NULL", ID: false}},
+	"col@text@null":              {col: sqlcup.Column{Name: "col", Type: "TEXT", Constraint: "", ID: false}},
+	"col@text@unique":            {col: sqlcup.Column{Name: "col", Type: "TEXT", Constraint: "NOT NULL UNIQUE", ID: false, Unique: true}},
+	"col@int":                    {col: sqlcup.Column{Name: "col", Type: "INTEGER", Constraint: "NOT NULL", ID: false}},
+	"col@datetime":               {col: sqlcup.Column{Name: "col", Type: "DATETIME", Constraint: "NOT NULL", ID: false}, err: nil},
+	"col@bigint":                 {col: sqlcup.Column{Name: "col", Type: "BIGINT", Constraint: "NOT NULL", ID: false}},
+	"col@smallint":               {col: sqlcup.Column{Name: "col", Type: "SMALLINT", Constraint: "NOT NULL"}},
+	"col_id@bigint@id":           {col: sqlcup.Column{Name: "col_id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true}},
+	"col_id@smallint@id":         {col: sqlcup.Column{Name: "col_id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true}},
+	"id@id@autoincrement":        {col: sqlcup.Column{Name: "id", Type: "INTEGER", Constraint: "PRIMARY KEY AUTOINCREMENT", ID: true}},
+	"id@bigint@id@autoincrement": {col: sqlcup.Column{Name: "id", Type: "INTEGER", Constraint: "PRIMARY KEY AUTOINCREMENT", ID: true}},
+	"id@text@id@autoincrement":   {err: errInvalidSmartColumn},
+	"n@int@autoincrement":        {err: errInvalidSmartColumn},
+	"col@bool":                   {col: sqlcup.Column{Name: "col", Type: "BOOLEAN", Constraint: "NOT NULL", ID: false}},
+	"col@bool@null":              {col: sqlcup.Column{Name: "col", Type: "BOOLEAN", Constraint: "", ID: false}},
+	"col@bool@unique":            {col: sqlcup.Column{Name: "col", Type: "BOOLEAN", Constraint: "NOT NULL UNIQUE", ID: false, Unique: true}},
+	"col@bool@default=true":      {col: sqlcup.Column{Name: "col", Type: "BOOLEAN", Constraint: "NOT NULL DEFAULT 1"}},
+	"col@bool@default=FALSE":     {col: sqlcup.Column{Name: "col", Type: "BOOLEAN", Constraint: "NOT NULL DEFAULT 0"}},
+	"col@text@default=true":      {col: sqlcup.Column{Name: "col", Type: "TEXT", Constraint: "NOT NULL DEFAULT true"}},
+
+	"col@datetime@default=CURRENT_TIMESTAMP": {col: sqlcup.Column{Name: "col", Type: "DATETIME", Constraint: "NOT NULL DEFAULT CURRENT_TIMESTAMP", ID: false}},
+	"col@int@unique@default=0":               {col: sqlcup.Column{Name: "col", Type: "INTEGER", Constraint: "NOT NULL DEFAULT 0 UNIQUE", ID: false, Unique: true}},
+	"col@int@null@default=0":                 {col: sqlcup.Column{Name: "col", Type: "INTEGER", Constraint: "DEFAULT 0", ID: false}},
+
+	"author_id@int@references=authors.id":                                     {col: sqlcup.Column{Name: "author_id", Type: "INTEGER", Constraint: "NOT NULL REFERENCES authors(id)", ID: false}},
+	"author_id@int@null@references=authors.id":                                {col: sqlcup.Column{Name: "author_id", Type: "INTEGER", Constraint: "REFERENCES authors(id)", ID: false}},
+	"author_id@int@references=authors":                                        {err: errInvalidSmartColumn},
+	"author_id@int@references=a.b.c":                                          {err: errInvalidSmartColumn},
+	"author_id@int@fk=authors.id@on-delete=cascade":                           {col: sqlcup.Column{Name: "author_id", Type: "INTEGER", Constraint: "NOT NULL REFERENCES authors(id) ON DELETE CASCADE"}},
+	"author_id@int@null@fk=authors.id@on-delete=set-null@on-update=no-action": {col: sqlcup.Column{Name: "author_id", Type: "INTEGER", Constraint: "REFERENCES authors(id) ON DELETE SET NULL ON UPDATE NO ACTION"}},
+	"author_id@int@fk=authors.id@on-update=drop":                              {err: errInvalidSmartColumn},
+	"author_id@int@on-delete=cascade":                                         {err: errInvalidSmartColumn},
+
+	"name@varchar=255": {col: sqlcup.Column{Name: "name", Type: "VARCHAR(255)", Constraint: "NOT NULL"}},
+	"name@varchar=0":   {err: errInvalidSmartColumn},
+	"name@varchar=-1":  {err: errInvalidSmartColumn},
+	"name@varchar=a":   {err: errInvalidSmartColumn},
+	"name@varchar":     {err: errInvalidSmartColumn},
+
+	"price@decimal=10,2":        {col: sqlcup.Column{Name: "price", Type: "DECIMAL(10,2)", Constraint: "NOT NULL"}},
+	"price@decimal=10,2@null":   {col: sqlcup.Column{Name: "price", Type: "DECIMAL(10,2)", Constraint: ""}},
+	"price@decimal=10,2@unique": {col: sqlcup.Column{Name: "price", Type: "DECIMAL(10,2)", Constraint: "NOT NULL UNIQUE", Unique: true}},
+	"price@decimal":             {col: sqlcup.Column{Name: "price", Type: "DECIMAL", Constraint: "NOT NULL"}},
+	"price@decimal=10":          {err: errInvalidSmartColumn},
+	"price@decimal=10,a":        {err: errInvalidSmartColumn},
+	"price@decimal=2,10":        {err: errInvalidSmartColumn},
+
+	"status@text@check=status IN ('active','inactive')": {col: sqlcup.Column{Name: "status", Type: "TEXT", Constraint: "NOT NULL CHECK (status IN ('active','inactive'))"}},
+	"email@text@check=email LIKE '%@%'@unique":          {col: sqlcup.Column{Name: "email", Type: "TEXT", Constraint: "NOT NULL UNIQUE CHECK (email LIKE '%@%')", Unique: true}},
+	"email@text@check=email LIKE '%@%.%'@null":          {col: sqlcup.Column{Name: "email", Type: "TEXT", Constraint: "CHECK (email LIKE '%@%.%')"}},
+	"status@text@check=":                                {err: errInvalidSmartColumn},
+
+	"col@uuid":    {col: sqlcup.Column{Name: "col", Type: "TEXT", Constraint: "NOT NULL"}},
+	"col@uuid@id": {col: sqlcup.Column{Name: "col", Type: "TEXT", Constraint: "NOT NULL PRIMARY KEY", ID: true}},
+
+	"doc@json":              {col: sqlcup.Column{Name: "doc", Type: "TEXT", Constraint: "NOT NULL"}},
+	"doc@jsonb@null":        {col: sqlcup.Column{Name: "doc", Type: "TEXT", Constraint: ""}},
+	"doc@json@default='{}'": {col: sqlcup.Column{Name: "doc", Type: "TEXT", Constraint: "NOT NULL DEFAULT '{}'"}},
+
+	"total@int@generated=price * qty":          {col: sqlcup.Column{Name: "total", Type: "INTEGER", Constraint: "GENERATED ALWAYS AS (price * qty) STORED NOT NULL", ReadOnly: true}},
+	"domain@text@generated=email@null@virtual": {col: sqlcup.Column{Name: "domain", Type: "TEXT", Constraint: "GENERATED ALWAYS AS (email) VIRTUAL", ReadOnly: true}},
+	"total@int@generated=":                     {err: errInvalidSmartColumn},
+	"total@int@virtual":                        {err: errInvalidSmartColumn},
+	"total@int@generated=1@default=2":          {err: errInvalidSmartColumn},
+
+	"status@enum=active,inactive":      {col: sqlcup.Column{Name: "status", Constraint: "NOT NULL", Enum: []string{"active", "inactive"}}},
+	"status@enum=active,,inactive":     {err: errInvalidSmartColumn},
+	"status@text@enum=active,inactive": {err: errInvalidSmartColumn},
+	"status@enum=active@id":            {err: errInvalidSmartColumn},
+
+	"email@text@comment=Login, e.g. jane@example.com@unique": {col: sqlcup.Column{Name: "email", Type: "TEXT", Constraint: "NOT NULL UNIQUE", Unique: true, Comment: "Login, e.g. jane@example.com"}},
+	"email@text@comment=": {err: errInvalidSmartColumn},
+
+	"created_at@datetime@default=CURRENT_TIMESTAMP@readonly": {col: sqlcup.Column{Name: "created_at", Type: "DATETIME", Constraint: "NOT NULL DEFAULT CURRENT_TIMESTAMP", ReadOnly: true}},
+}
+
+var postgresSmartColTests = smartColTestCases{
+	"@id":                {col: sqlcup.Column{Name: "id", Type: "SERIAL", Constraint: "PRIMARY KEY", ID: true}},
+	"col_id@int@id":      {col: sqlcup.Column{Name: "col_id", Type: "SERIAL", Constraint: "PRIMARY KEY", ID: true}},
+	"col_id@bigint@id":   {col: sqlcup.Column{Name: "col_id", Type: "BIGSERIAL", Constraint: "PRIMARY KEY", ID: true}},
+	"col_id@smallint@id": {col: sqlcup.Column{Name: "col_id", Type: "SMALLSERIAL", Constraint: "PRIMARY KEY", ID: true}},
+	"col_id@uuid@id":     {col: sqlcup.Column{Name: "col_id", Type: "UUID", Constraint: "PRIMARY KEY", ID: true}},
+	"col_id@uuid@id@default=gen_random_uuid()": {col: sqlcup.Column{Name: "col_id", Type: "UUID", Constraint: "PRIMARY KEY DEFAULT gen_random_uuid()", ID: true}},
+	"primary_key@text@id":                      {col: sqlcup.Column{Name: "primary_key", Type: "TEXT", Constraint: "PRIMARY KEY", ID: true}},
+	"col@int":                                  {col: sqlcup.Column{Name: "col", Type: "INTEGER", Constraint: "NOT NULL", ID: false}},
+	"col@bool":                                 {col: sqlcup.Column{Name: "col", Type: "BOOLEAN", Constraint: "NOT NULL", ID: false}},
+	"col@bool@default=true":                    {col: sqlcup.Column{Name: "col", Type: "BOOLEAN", Constraint: "NOT NULL DEFAULT TRUE"}},
+	"doc@json":                                 {col: sqlcup.Column{Name: "doc", Type: "JSON", Constraint: "NOT NULL"}},
+	"doc@jsonb@null":                           {col: sqlcup.Column{Name: "doc", Type: "JSONB", Constraint: ""}},
+	"id@id@autoincrement":                      {err: errInvalidSmartColumn},
+	"col@datetime":                             {col: sqlcup.Column{Name: "col", Type: "TIMESTAMP", Constraint: "NOT NULL"}},
+	"col@tz@datetime@null":                     {col: sqlcup.Column{Name: "col", Type: "TIMESTAMPTZ", Constraint: ""}},
+	"col@text@tz":                              {err: errInvalidSmartColumn},
+	"total@int@generated=price * qty@virtual": {err: errInvalidSmartColumn},
+}
+
+var mysqlSmartColTests = smartColTestCases{
+	"@id":                    {col: sqlcup.Column{Name: "id", Type: "INT", Constraint: "AUTO_INCREMENT PRIMARY KEY", ID: true}},
+	"col_id@bigint@id":       {col: sqlcup.Column{Name: "col_id", Type: "BIGINT", Constraint: "AUTO_INCREMENT PRIMARY KEY", ID: true}},
+	"col_id@smallint@id":     {col: sqlcup.Column{Name: "col_id", Type: "SMALLINT", Constraint: "AUTO_INCREMENT PRIMARY KEY", ID: true}},
+	"col@bool":               {col: sqlcup.Column{Name: "col", Type: "TINYINT(1)", Constraint: "NOT NULL", ID: false}},
+	"col@bool@null":          {col: sqlcup.Column{Name: "col", Type: "TINYINT(1)", Constraint: "", ID: false}},
+	"col@bool@default=false": {col: sqlcup.Column{Name: "col", Type: "TINYINT(1)", Constraint: "NOT NULL DEFAULT 0"}},
+	"col@uuid":               {col: sqlcup.Column{Name: "col", Type: "CHAR(36)", Constraint: "NOT NULL"}},
+	"col@uuid@id":            {col: sqlcup.Column{Name: "col", Type: "CHAR(36)", Constraint: "PRIMARY KEY", ID: true}},
+	"doc@json":               {col: sqlcup.Column{Name: "doc", Type: "JSON", Constraint: "NOT NULL"}},
+	"doc@jsonb":              {col: sqlcup.Column{Name: "doc", Type: "JSON", Constraint: "NOT NULL"}},
+	"col@datetime@tz":        {err: errInvalidSmartColumn},
 }
 
 func TestParseSmartColumnDefinition(t *testing.T) {
-	for def, want := range smartColTests {
+	testParseSmartColumnDefinition(t, sqlcup.DialectSQLite, smartColTests)
+}
+
+func TestParseSmartColumnDefinitionPostgres(t *testing.T) {
+	testParseSmartColumnDefinition(t, sqlcup.DialectPostgres, postgresSmartColTests)
+}
+
+func TestParseSmartColumnDefinitionMySQL(t *testing.T) {
+	testParseSmartColumnDefinition(t, sqlcup.DialectMySQL, mysqlSmartColTests)
+}
+
+func testParseSmartColumnDefinition(t *testing.T, d sqlcup.Dialect, tests smartColTestCases) {
+	for def, want := range tests {
 		t.Run(def, func(t *testing.T) {
-			got, err := parseSmartColumnDefinition(def)
-			if diff := cmp.Diff(want.err, err); diff != "" {
+			got, err := parseSmartColumnDefinition(def, d, false)
+			if diff := cmp.Diff(want.err, err, cmpopts.EquateErrors()); diff != "" {
 				t.Errorf("parseSmartColumnDefinition(\"%s\") returned wrong error: diff -want +got\n%s", def, diff)
 			}
 			if diff := cmp.Diff(want.col, got); diff != "" {
@@ -32,3 +163,951 @@ func TestParseSmartColumnDefinition(t *testing.T) {
 		})
 	}
 }
+
+type plainColTestCases map[string]struct {
+	col sqlcup.Column
+	err error
+}
+
+var plainColTests = plainColTestCases{
+	"id:INTEGER:PRIMARY KEY":               {col: sqlcup.Column{Name: "id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true}},
+	"name:TEXT":                            {col: sqlcup.Column{Name: "name", Type: "TEXT"}},
+	"email:TEXT:NOT NULL UNIQUE":           {col: sqlcup.Column{Name: "email", Type: "TEXT", Constraint: "NOT NULL UNIQUE", Unique: true}},
+	"price:INTEGER:NOT NULL DEFAULT 0":     {col: sqlcup.Column{Name: "price", Type: "INTEGER", Constraint: "NOT NULL DEFAULT 0"}},
+	"opens:TIME:DEFAULT '08:00:00'":        {col: sqlcup.Column{Name: "opens", Type: "TIME", Constraint: "DEFAULT '08:00:00'"}},
+	"bio:TEXT?":                            {col: sqlcup.Column{Name: "bio", Type: "TEXT"}},
+	"bio:TEXT?:DEFAULT ''":                 {col: sqlcup.Column{Name: "bio", Type: "TEXT", Constraint: "DEFAULT ''"}},
+	"bio:TEXT?:NOT NULL":                   {err: errBadArgument},
+	"bio:?":                                {err: errBadArgument},
+	"ts:TIMESTAMP WITH TIME ZONE:NOT NULL": {col: sqlcup.Column{Name: "ts", Type: "TIMESTAMP WITH TIME ZONE", Constraint: "NOT NULL"}},
+	"ts:TIMESTAMP WITH TIME ZONE?:DEFAULT '2000-01-01 00:00:00+00'": {col: sqlcup.Column{Name: "ts", Type: "TIMESTAMP WITH TIME ZONE", Constraint: "DEFAULT '2000-01-01 00:00:00+00'"}},
+	"ratio:DOUBLE PRECISION": {col: sqlcup.Column{Name: "ratio", Type: "DOUBLE PRECISION"}},
+}
+
+var plainNotNullColTests = plainColTestCases{
+	"id:INTEGER:PRIMARY KEY":    {col: sqlcup.Column{Name: "id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true}},
+	"name:TEXT":                 {col: sqlcup.Column{Name: "name", Type: "TEXT", Constraint: "NOT NULL"}},
+	"name:TEXT:UNIQUE":          {col: sqlcup.Column{Name: "name", Type: "TEXT", Constraint: "NOT NULL UNIQUE", Unique: true}},
+	"name:TEXT:NOT NULL UNIQUE": {col: sqlcup.Column{Name: "name", Type: "TEXT", Constraint: "NOT NULL UNIQUE", Unique: true}},
+	"bio:TEXT?":                 {col: sqlcup.Column{Name: "bio", Type: "TEXT"}},
+}
+
+func TestParsePlainColumnDefinitionNotNullDefault(t *testing.T) {
+	*plainNotNullDefaultFlag = true
+	defer func() { *plainNotNullDefaultFlag = false }()
+	testParsePlainColumnDefinition(t, plainNotNullColTests)
+}
+
+func TestParsePlainColumnDefinition(t *testing.T) {
+	testParsePlainColumnDefinition(t, plainColTests)
+}
+
+func testParsePlainColumnDefinition(t *testing.T, tests plainColTestCases) {
+	for def, want := range tests {
+		t.Run(def, func(t *testing.T) {
+			got, err := parsePlainColumnDefinition(def)
+			if diff := cmp.Diff(want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("parsePlainColumnDefinition(\"%s\") returned wrong error: diff -want +got\n%s", def, diff)
+			}
+			if diff := cmp.Diff(want.col, got); diff != "" {
+				t.Errorf("parsePlainColumnDefinition(\"%s\") returned wrong column: diff -want +got\n%s", def, diff)
+			}
+		})
+	}
+}
+
+var identifierTests = map[string]struct {
+	allowQuoted bool
+	want        string
+	err         error
+}{
+	"name":     {want: "name"},
+	"_name2":   {want: "_name2"},
+	`"my col"`: {want: `"my col"`},
+	"`my col`": {want: "`my col`"},
+	"my col":   {err: errBadArgument},
+	"2name":    {err: errBadArgument},
+	"select":   {err: errBadArgument},
+	"Order":    {allowQuoted: true, want: `"Order"`},
+	`say "hi"`: {allowQuoted: true, want: `"say ""hi"""`},
+}
+
+func TestCheckIdentifier(t *testing.T) {
+	for name, tt := range identifierTests {
+		t.Run(name, func(t *testing.T) {
+			got, err := checkIdentifier(name, sqlcup.DialectSQLite, tt.allowQuoted)
+			if diff := cmp.Diff(tt.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("checkIdentifier(\"%s\") returned wrong error: diff -want +got\n%s", name, diff)
+			}
+			if got != tt.want {
+				t.Errorf("checkIdentifier(\"%s\") = %s, want %s", name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScaffoldCommandArgsMultipleTables(t *testing.T) {
+	tables, err := parseScaffoldCommandArgs([]string{"author/authors", "@id", "--", "book/books", "@id", "title@text", "--"})
+	if err != nil {
+		t.Fatalf("parseScaffoldCommandArgs() returned error: %v", err)
+	}
+	var got []string
+	for _, sca := range tables {
+		got = append(got, fmt.Sprintf("%s:%d", sca.Table, len(sca.Columns)))
+	}
+	if diff := cmp.Diff([]string{"authors:1", "books:2"}, got); diff != "" {
+		t.Errorf("parseScaffoldCommandArgs() returned wrong tables: diff -want +got\n%s", diff)
+	}
+
+	_, err = parseScaffoldCommandArgs([]string{"author/authors", "@id", "--", "--", "book/books", "@id"})
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("parseScaffoldCommandArgs() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestParseScaffoldCommandArgsIDFirst(t *testing.T) {
+	*idFirstFlag = true
+	defer func() { *idFirstFlag = false }()
+	tables, err := parseScaffoldCommandArgs([]string{"book/books", "title@text", "@id", "isbn@text"})
+	if err != nil {
+		t.Fatalf("parseScaffoldCommandArgs() returned error: %v", err)
+	}
+	var got []string
+	for _, col := range tables[0].Columns {
+		got = append(got, col.Name)
+	}
+	if diff := cmp.Diff([]string{"id", "title", "isbn"}, got); diff != "" {
+		t.Errorf("parseScaffoldCommandArgs() returned wrong column order: diff -want +got\n%s", diff)
+	}
+}
+
+var singularizeTests = map[string]struct {
+	want string
+	ok   bool
+}{
+	"users":      {want: "user", ok: true},
+	"categories": {want: "category", ok: true},
+	"cities":     {want: "city", ok: true},
+	"s":          {},
+	"address":    {},
+	"sheep":      {},
+}
+
+func TestSingularize(t *testing.T) {
+	for plural, tt := range singularizeTests {
+		got, ok := singularize(plural)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("singularize(\"%s\") = %s, %v, want %s, %v", plural, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+var snakeCaseTests = map[string]string{
+	"author":          "author",
+	"zipcode_imports": "zipcode_imports",
+	"ZipcodeImports":  "zipcode_imports",
+	"zipcode-imports": "zipcode_imports",
+	"HTTPLogs":        "http_logs",
+	"Zipcode_Imports": "zipcode_imports",
+	"v2Users":         "v2_users",
+}
+
+func TestSnakeCase(t *testing.T) {
+	for s, want := range snakeCaseTests {
+		if got := snakeCase(s); got != want {
+			t.Errorf("snakeCase(\"%s\") = %s, want %s", s, got, want)
+		}
+	}
+}
+
+var orderByTests = map[string]struct {
+	def      string
+	perQuery map[string]string
+	err      error
+}{
+	"name DESC, id":                       {def: "name DESC, id"},
+	"list=name":                           {perQuery: map[string]string{"": "name"}},
+	"id;listByStatus=created_at DESC":     {def: "id", perQuery: map[string]string{"status": "created_at DESC"}},
+	"list=name; listbystatus=id; id DESC": {def: "id DESC", perQuery: map[string]string{"": "name", "status": "id"}},
+	"listByEmail=id":                      {err: errBadArgument},
+	"id;name":                             {err: errBadArgument},
+}
+
+func TestParseOrderBy(t *testing.T) {
+	for s, want := range orderByTests {
+		t.Run(s, func(t *testing.T) {
+			def, perQuery, err := parseOrderBy(s, []string{"status"})
+			if diff := cmp.Diff(want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("parseOrderBy(\"%s\") returned wrong error: diff -want +got\n%s", s, diff)
+			}
+			if def != want.def {
+				t.Errorf("parseOrderBy(\"%s\") returned default %s, want %s", s, def, want.def)
+			}
+			if diff := cmp.Diff(want.perQuery, perQuery, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("parseOrderBy(\"%s\") returned wrong per-query terms: diff -want +got\n%s", s, diff)
+			}
+		})
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	config := "# defaults\ndialect: postgres\n\ntimestamps: true\norder-by: \"name DESC\"\n"
+	got, err := readConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("readConfig() returned error: %v", err)
+	}
+	want := []configSetting{
+		{line: 2, name: "dialect", value: "postgres"},
+		{line: 4, name: "timestamps", value: "true"},
+		{line: 5, name: "order-by", value: "name DESC"},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(configSetting{})); diff != "" {
+		t.Errorf("readConfig() returned wrong settings: diff -want +got\n%s", diff)
+	}
+
+	if _, err := readConfig(strings.NewReader("dialect postgres\n")); err == nil {
+		t.Errorf("readConfig() with missing colon returned no error")
+	}
+}
+
+func TestParseScaffoldCommandArgsTablePrefix(t *testing.T) {
+	*tablePrefixFlag = "app_"
+	defer func() { *tablePrefixFlag = "" }()
+	tables, err := parseScaffoldCommandArgs([]string{"user/users", "@id"})
+	if err != nil {
+		t.Fatalf("parseScaffoldCommandArgs() returned error: %v", err)
+	}
+	if got := tables[0]; got.Table != "app_users" || got.SingularEntity != "User" || got.PluralEntity != "Users" {
+		t.Errorf("parseScaffoldCommandArgs() returned table %s with entities %s/%s, want app_users with User/Users", got.Table, got.SingularEntity, got.PluralEntity)
+	}
+}
+
+func TestParseScaffoldCommandArgsLowerSnakeTable(t *testing.T) {
+	*lowerSnakeTableFlag = true
+	defer func() { *lowerSnakeTableFlag = false }()
+	tables, err := parseScaffoldCommandArgs([]string{"UserAccount/UserAccounts", "@id", "--", "HTTPLogs", "@id"})
+	if err != nil {
+		t.Fatalf("parseScaffoldCommandArgs() returned error: %v", err)
+	}
+	if got := tables[0]; got.Table != "user_accounts" || got.SingularEntity != "UserAccount" || got.PluralEntity != "UserAccounts" {
+		t.Errorf("parseScaffoldCommandArgs() returned table %s with entities %s/%s, want user_accounts with UserAccount/UserAccounts", got.Table, got.SingularEntity, got.PluralEntity)
+	}
+	if got := tables[1]; got.Table != "http_logs" || got.SingularEntity != "HTTPLog" || got.PluralEntity != "HTTPLogs" {
+		t.Errorf("parseScaffoldCommandArgs() returned table %s with entities %s/%s, want http_logs with HTTPLog/HTTPLogs", got.Table, got.SingularEntity, got.PluralEntity)
+	}
+
+	sca, err := parseTableSpec(strings.NewReader(`{"plural":"UserAccounts","columns":[{"name":"id","type":"INTEGER","id":true}]}`))
+	if err != nil {
+		t.Fatalf("parseTableSpec() returned error: %v", err)
+	}
+	if sca.Table != "user_accounts" {
+		t.Errorf("parseTableSpec() returned table %s, want user_accounts", sca.Table)
+	}
+}
+
+func TestDescribeColumns(t *testing.T) {
+	tables, err := parseScaffoldCommandArgs([]string{"user/users", "@id", "email@text@unique"})
+	if err != nil {
+		t.Fatalf("parseScaffoldCommandArgs() returned error: %v", err)
+	}
+	b := &strings.Builder{}
+	describeColumns(b, tables)
+	want := `table 'users'
+col 'id' -> INTEGER, constraint 'PRIMARY KEY', id=true
+col 'email' -> TEXT, constraint 'NOT NULL UNIQUE', id=false
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("describeColumns() wrote wrong description: diff -want +got\n%s", diff)
+	}
+}
+
+func TestNoteMissingID(t *testing.T) {
+	tests := []struct {
+		name   string
+		output outputMode
+		cols   []sqlcup.Column
+		note   bool
+	}{
+		{name: "id column", output: outputAll, cols: []sqlcup.Column{{Name: "id", ID: true}, {Name: "name"}}},
+		{name: "no id column", output: outputAll, cols: []sqlcup.Column{{Name: "name"}}, note: true},
+		{name: "no id column without queries", output: outputSchema, cols: []sqlcup.Column{{Name: "name"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sca := &scaffoldCommandArgs{Args: sqlcup.Args{Table: "tags"}, Output: tt.output}
+			b := &strings.Builder{}
+			noteMissingID(b, sca, tt.cols)
+			if got := strings.Contains(b.String(), "note: no id column detected in table tags"); got != tt.note {
+				t.Errorf("noteMissingID() wrote %q, want note %t", b.String(), tt.note)
+			}
+		})
+	}
+}
+
+func TestParseTableArgsNoColumns(t *testing.T) {
+	_, err := parseTableArgs([]string{"user/users"}, false)
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("parseTableArgs() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestParseTableArgsDuplicateColumns(t *testing.T) {
+	_, err := parseTableArgs([]string{"user/users", "name:TEXT", "Name:INTEGER"}, false)
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("parseTableArgs() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestParseTableArgsEntityNames(t *testing.T) {
+	sca, err := parseTableArgs([]string{"_data/_datas", "@id"}, false)
+	if err != nil {
+		t.Fatalf("parseTableArgs() returned error: %v", err)
+	}
+	if sca.SingularEntity != "Data" || sca.PluralEntity != "Datas" {
+		t.Errorf("parseTableArgs() returned entities %s/%s, want Data/Datas", sca.SingularEntity, sca.PluralEntity)
+	}
+	for _, name := range []string{"2fa/2fas", "_/__"} {
+		_, err := parseTableArgs([]string{name, "@id"}, false)
+		if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("parseTableArgs() with <name> '%s' returned wrong error: diff -want +got\n%s", name, diff)
+		}
+	}
+}
+
+func TestParseTableArgsExcludeFromInsert(t *testing.T) {
+	*excludeFromInsertFlag = "Created"
+	defer func() { *excludeFromInsertFlag = "" }()
+	sca, err := parseTableArgs([]string{"user/users", "id:INTEGER:PRIMARY KEY", "created:DATETIME"}, false)
+	if err != nil {
+		t.Fatalf("parseTableArgs() returned error: %v", err)
+	}
+	if got := sca.Columns[1]; !got.ReadOnly {
+		t.Errorf("parseTableArgs() returned column %+v, want read-only column", got)
+	}
+
+	*excludeFromInsertFlag = "updated"
+	_, err = parseTableArgs([]string{"user/users", "id:INTEGER:PRIMARY KEY", "created:DATETIME"}, false)
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("parseTableArgs() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestParseTableArgsStrictColumns(t *testing.T) {
+	*strictColumnsFlag = true
+	defer func() { *strictColumnsFlag = false }()
+	if _, err := parseTableArgs([]string{"user/users", "@id", "name@text"}, false); err != nil {
+		t.Errorf("parseTableArgs() returned error: %v", err)
+	}
+	_, err := parseTableArgs([]string{"user/users", "@id", "name:TEXT"}, false)
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("parseTableArgs() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestSqlcConfig(t *testing.T) {
+	args := &scaffoldCommandArgs{Args: sqlcup.Args{Dialect: sqlcup.DialectPostgres}, QueriesOut: "db/query.sql"}
+	want := `version: "2"
+sql:
+  - engine: "postgresql"
+    schema: "schema.sql"
+    queries: "db/query.sql"
+    gen:
+      go:
+        package: "db"
+        out: "db"
+`
+	if diff := cmp.Diff(want, sqlcConfig([]*scaffoldCommandArgs{args})); diff != "" {
+		t.Errorf("sqlcConfig() returned wrong config: diff -want +got\n%s", diff)
+	}
+}
+
+func TestSqlcConfigMultipleTables(t *testing.T) {
+	tables := []*scaffoldCommandArgs{
+		{Args: sqlcup.Args{Dialect: sqlcup.DialectSQLite}, SchemaOut: "db/schema.sql", QueriesOut: "db/authors.sql"},
+		{Args: sqlcup.Args{Dialect: sqlcup.DialectSQLite}, SchemaOut: "db/schema.sql", QueriesOut: "db/books.sql"},
+	}
+	want := `version: "2"
+sql:
+  - engine: "sqlite"
+    schema: "db/schema.sql"
+    queries: ["db/authors.sql", "db/books.sql"]
+    gen:
+      go:
+        package: "db"
+        out: "db"
+`
+	if diff := cmp.Diff(want, sqlcConfig(tables)); diff != "" {
+		t.Errorf("sqlcConfig() returned wrong config: diff -want +got\n%s", diff)
+	}
+}
+
+func TestParseScaffoldCommandArgsUnknownOrderByColumn(t *testing.T) {
+	*orderByFlag = "craeted_at"
+	defer func() { *orderByFlag = "" }()
+	_, err := parseScaffoldCommandArgs([]string{"author/authors", "@id", "--", "book/books", "@id", "created_at@datetime"})
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("parseScaffoldCommandArgs() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		b := &strings.Builder{}
+		if err := completionCommand(b, []string{shell}); err != nil {
+			t.Fatalf("completionCommand() for %s returned error: %v", shell, err)
+		}
+		if !strings.Contains(b.String(), "dialect") {
+			t.Errorf("completionCommand() for %s wrote script without -dialect:\n%s", shell, b)
+		}
+	}
+	err := completionCommand(io.Discard, []string{"tcsh"})
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("completionCommand() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestParseTableSpec(t *testing.T) {
+	spec := `{"table":"users","columns":[{"name":"id","type":"INTEGER","constraint":"PRIMARY KEY","id":true},{"name":"email","type":"TEXT","unique":true}]}`
+	sca, err := parseTableSpec(strings.NewReader(spec))
+	if err != nil {
+		t.Fatalf("parseTableSpec() returned error: %v", err)
+	}
+	want := []sqlcup.Column{
+		{Name: "id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true},
+		{Name: "email", Type: "TEXT", Unique: true, Nullable: true},
+	}
+	if diff := cmp.Diff(want, sca.Columns); diff != "" {
+		t.Errorf("parseTableSpec() returned wrong columns: diff -want +got\n%s", diff)
+	}
+	if sca.Table != "users" || sca.SingularEntity != "User" || sca.PluralEntity != "Users" {
+		t.Errorf("parseTableSpec() returned table %q and entities %q/%q, want users and User/Users", sca.Table, sca.SingularEntity, sca.PluralEntity)
+	}
+
+	for _, spec := range []string{
+		`{"table":"users","columns":[]}`,
+		`{"table":"users","columns":[{"name":"id","typ":"INTEGER"}]}`,
+		`{"columns":[{"name":"id","type":"INTEGER"}]}`,
+	} {
+		_, err := parseTableSpec(strings.NewReader(spec))
+		if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("parseTableSpec(%s) returned wrong error: diff -want +got\n%s", spec, diff)
+		}
+	}
+}
+
+func TestScaffoldTableDesc(t *testing.T) {
+	*descFlag = "User accounts"
+	defer func() { *descFlag = "" }()
+	sca, err := parseTableArgs([]string{"user/users", "@id"}, false)
+	if err != nil {
+		t.Fatalf("parseTableArgs() returned error: %v", err)
+	}
+	out, err := scaffoldTable(sca, false)
+	if err != nil {
+		t.Fatalf("scaffoldTable() returned error: %v", err)
+	}
+	for _, want := range []string{"-- User accounts\nCREATE TABLE", "-- User accounts\n-- name: GetUser :one"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("scaffoldTable() returned output without %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSqliteSupportsReturning(t *testing.T) {
+	for version, want := range map[string]bool{"3.30": false, "3.34.1": false, "3.35": true, "3.45.0": true, "4.0": true} {
+		got, err := sqliteSupportsReturning(version)
+		if err != nil {
+			t.Fatalf("sqliteSupportsReturning(%q) returned error: %v", version, err)
+		}
+		if got != want {
+			t.Errorf("sqliteSupportsReturning(%q) = %t, want %t", version, got, want)
+		}
+	}
+	for _, version := range []string{"3", "3.x", "3.35.0.1", ""} {
+		_, err := sqliteSupportsReturning(version)
+		if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("sqliteSupportsReturning(%q) returned wrong error: diff -want +got\n%s", version, diff)
+		}
+	}
+}
+
+func TestScaffoldTableSectionOrder(t *testing.T) {
+	*sectionOrderFlag = "queries,schema"
+	defer func() { *sectionOrderFlag = "schema,queries" }()
+	sca, err := parseTableArgs([]string{"user/users", "@id"}, false)
+	if err != nil {
+		t.Fatalf("parseTableArgs() returned error: %v", err)
+	}
+	out, err := scaffoldTable(sca, false)
+	if err != nil {
+		t.Fatalf("scaffoldTable() returned error: %v", err)
+	}
+	if strings.Index(out, "CREATE TABLE") < strings.Index(out, "-- name: GetUser") {
+		t.Errorf("scaffoldTable() returned schema before queries:\n%s", out)
+	}
+
+	*sectionOrderFlag = "schema"
+	_, err = parseTableArgs([]string{"user/users", "@id"}, false)
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("parseTableArgs() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestScaffoldCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "single table",
+			args: []string{"user/users", "@id", "name@text"},
+			want: `-----------------------------------------------
+-- Add the following to your SQL schema file --
+-----------------------------------------------
+
+CREATE TABLE IF NOT EXISTS users (
+  id   INTEGER PRIMARY KEY,
+  name TEXT    NOT NULL
+);
+
+------------------------------------------------
+-- Add the following to your SQL queries file --
+------------------------------------------------
+
+-- name: GetUser :one
+SELECT * FROM users
+WHERE id = ? LIMIT 1;
+
+-- name: UserExists :one
+SELECT EXISTS(SELECT 1 FROM users WHERE id = ?);
+
+-- name: ListUsers :many
+SELECT * FROM users;
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users;
+
+-- name: CreateUser :one
+INSERT INTO users (
+  name
+) VALUES (
+  ?
+)
+RETURNING *;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?;
+
+-- name: UpdateUser :one
+UPDATE users
+SET
+  name = ?
+WHERE id = ?
+RETURNING *;
+`,
+		},
+		{
+			name: "multiple tables",
+			args: []string{"tags", "@id", "name@text", "--", "post/posts", "@id", "title@text"},
+			want: `----------------
+-- Table tags --
+----------------
+
+-----------------------------------------------
+-- Add the following to your SQL schema file --
+-----------------------------------------------
+
+CREATE TABLE IF NOT EXISTS tags (
+  id   INTEGER PRIMARY KEY,
+  name TEXT    NOT NULL
+);
+
+------------------------------------------------
+-- Add the following to your SQL queries file --
+------------------------------------------------
+
+-- name: GetTag :one
+SELECT * FROM tags
+WHERE id = ? LIMIT 1;
+
+-- name: TagExists :one
+SELECT EXISTS(SELECT 1 FROM tags WHERE id = ?);
+
+-- name: ListTags :many
+SELECT * FROM tags;
+
+-- name: CountTags :one
+SELECT COUNT(*) FROM tags;
+
+-- name: CreateTag :one
+INSERT INTO tags (
+  name
+) VALUES (
+  ?
+)
+RETURNING *;
+
+-- name: DeleteTag :exec
+DELETE FROM tags
+WHERE id = ?;
+
+-- name: UpdateTag :one
+UPDATE tags
+SET
+  name = ?
+WHERE id = ?
+RETURNING *;
+
+-----------------
+-- Table posts --
+-----------------
+
+-----------------------------------------------
+-- Add the following to your SQL schema file --
+-----------------------------------------------
+
+CREATE TABLE IF NOT EXISTS posts (
+  id    INTEGER PRIMARY KEY,
+  title TEXT    NOT NULL
+);
+
+------------------------------------------------
+-- Add the following to your SQL queries file --
+------------------------------------------------
+
+-- name: GetPost :one
+SELECT * FROM posts
+WHERE id = ? LIMIT 1;
+
+-- name: PostExists :one
+SELECT EXISTS(SELECT 1 FROM posts WHERE id = ?);
+
+-- name: ListPosts :many
+SELECT * FROM posts;
+
+-- name: CountPosts :one
+SELECT COUNT(*) FROM posts;
+
+-- name: CreatePost :one
+INSERT INTO posts (
+  title
+) VALUES (
+  ?
+)
+RETURNING *;
+
+-- name: DeletePost :exec
+DELETE FROM posts
+WHERE id = ?;
+
+-- name: UpdatePost :one
+UPDATE posts
+SET
+  title = ?
+WHERE id = ?
+RETURNING *;
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := parseScaffoldCommandArgs(tt.args)
+			if err != nil {
+				t.Fatalf("parseScaffoldCommandArgs() returned error: %v", err)
+			}
+			b := &strings.Builder{}
+			if err := scaffoldCommand(b, tables); err != nil {
+				t.Fatalf("scaffoldCommand() returned error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, b.String()); diff != "" {
+				t.Errorf("scaffoldCommand() returned wrong output: diff -want +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScaffoldCommandJSON(t *testing.T) {
+	*formatFlag = "json"
+	defer func() { *formatFlag = "text" }()
+	tables, err := parseScaffoldCommandArgs([]string{"tags", "@id", "name@text", "--", "post/posts", "@id", "title@text"})
+	if err != nil {
+		t.Fatalf("parseScaffoldCommandArgs() returned error: %v", err)
+	}
+	b := &strings.Builder{}
+	if err := scaffoldCommand(b, tables); err != nil {
+		t.Fatalf("scaffoldCommand() returned error: %v", err)
+	}
+	var got []struct {
+		Schema  string `json:"schema"`
+		Queries []struct {
+			Name string `json:"name"`
+		} `json:"queries"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("scaffoldCommand() returned invalid JSON: %v\n%s", err, b.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("scaffoldCommand() returned %d tables, want 2", len(got))
+	}
+	for i, table := range []string{"tags", "posts"} {
+		if !strings.Contains(got[i].Schema, "CREATE TABLE IF NOT EXISTS "+table) {
+			t.Errorf("scaffoldCommand() returned wrong schema for %s:\n%s", table, got[i].Schema)
+		}
+		if len(got[i].Queries) == 0 {
+			t.Errorf("scaffoldCommand() returned no queries for %s", table)
+		}
+	}
+}
+
+func TestScaffoldCommandLowercaseKeywords(t *testing.T) {
+	*keywordCaseFlag, *dialectFlag, *timestampsFlag = "lower", "postgres", true
+	defer func() { *keywordCaseFlag, *dialectFlag, *timestampsFlag = "upper", "sqlite", false }()
+	tables, err := parseScaffoldCommandArgs([]string{
+		"book/books", "@id", "title@text@unique@check=length(title) > 0", "author_id@int@fk=authors.id@on-delete=cascade",
+		"published@bool@default=TRUE", "slug@text@generated=lower(title)", "isbn:TEXT:NOT NULL UNIQUE",
+	})
+	if err != nil {
+		t.Fatalf("parseScaffoldCommandArgs() returned error: %v", err)
+	}
+	b := &strings.Builder{}
+	if err := scaffoldCommand(b, tables); err != nil {
+		t.Fatalf("scaffoldCommand() returned error: %v", err)
+	}
+	keywords := regexp.MustCompile(`\b(PRIMARY KEY|NOT NULL|DEFAULT|REFERENCES|ON DELETE|CASCADE|CHECK|UNIQUE|GENERATED ALWAYS AS|STORED|CURRENT_TIMESTAMP|TRUE)\b`)
+	for _, line := range strings.Split(b.String(), "\n") {
+		// The constraint of a plain column is used verbatim.
+		if strings.Contains(line, "isbn ") && strings.Contains(line, "TEXT") {
+			if !strings.HasSuffix(line, "NOT NULL UNIQUE,") {
+				t.Errorf("scaffoldCommand() changed the constraint of a plain column: %s", line)
+			}
+			continue
+		}
+		if m := keywords.FindAllString(line, -1); m != nil {
+			t.Errorf("scaffoldCommand() returned uppercase keywords %v: %s", m, line)
+		}
+	}
+}
+
+// goldenTests maps the name of each golden file in testdata to the command line that produces it.
+var goldenTests = map[string][]string{
+	"nullable_hints":    {"-null-as-pointer-hint", "-soft-delete", "-only", "schema", "author/authors", "@id", "bio@text@null", "email:TEXT", "name:TEXT:NOT NULL"},
+	"sqlite":            {"author/authors", "@id", "name@text", "bio@text@null", "email@text@unique"},
+	"postgres":          {"-dialect", "postgres", "-timestamps", "-soft-delete", "author/authors", "id@uuid@id@default=gen_random_uuid()", "name@varchar=100", "active@bool@default=true"},
+	"mysql":             {"-dialect", "mysql", "-upsert", "-no-count", "tags", "@id", "name@text@unique"},
+	"composite_key":     {"-explicit-columns", "post_tag/post_tags", "post_id@int@id", "tag_id@int@id", "position@int"},
+	"filters":           {"-filter-by", "author_id", "-order-by", "title", "-paginate", "-named-params", "book/books", "@id", "author_id@int@references=authors.id", "title@text"},
+	"multiple":          {"-no-banners", "-keyword-case", "lower", "tags", "@id", "name@text", "--", "post/posts", "@id", "title@text"},
+	"entity_case_snake": {"-entity-case", "snake", "-queries-only", "UserAccount/UserAccounts", "@id", "name@text"},
+	"entity_case_raw":   {"-entity-case", "raw", "-queries-only", "user_account/user_accounts", "@id", "name@text"},
+	"partial_update":    {"-partial-updates", "-coalesce-update", "-queries-only", "user/users", "@id", "name@text", "email@text"},
+}
+
+func TestGolden(t *testing.T) {
+	for name, args := range goldenTests {
+		t.Run(name, func(t *testing.T) {
+			defer resetFlags()
+			if err := flag.CommandLine.Parse(args); err != nil {
+				t.Fatalf("Parse() returned error: %v", err)
+			}
+			tables, err := parseScaffoldCommandArgs(flag.CommandLine.Args())
+			if err != nil {
+				t.Fatalf("parseScaffoldCommandArgs() returned error: %v", err)
+			}
+			b := &strings.Builder{}
+			if err := scaffoldCommand(b, tables); err != nil {
+				t.Fatalf("scaffoldCommand() returned error: %v", err)
+			}
+
+			path := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(want), b.String()); diff != "" {
+				t.Errorf("scaffoldCommand() returned output that differs from %s: diff -want +got\n%s", path, diff)
+			}
+		})
+	}
+}
+
+// resetFlags sets all flags of sqlcup back to their defaults.
+func resetFlags() {
+	flag.VisitAll(func(f *flag.Flag) {
+		if strings.HasPrefix(f.Name, "test.") || f.Name == "update" {
+			return
+		}
+		if list, ok := f.Value.(*stringListFlag); ok {
+			*list = nil
+			return
+		}
+		// The defaults of all flags are valid values.
+		_ = f.Value.Set(f.DefValue)
+	})
+}
+
+func TestSplitFields(t *testing.T) {
+	got, err := splitFields(`author/authors  @id 'bio:TEXT:DEFAULT ""' "name:TEXT:NOT NULL"`)
+	if err != nil {
+		t.Fatalf("splitFields() returned error: %v", err)
+	}
+	want := []string{"author/authors", "@id", `bio:TEXT:DEFAULT ""`, "name:TEXT:NOT NULL"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("splitFields() returned wrong fields: diff -want +got\n%s", diff)
+	}
+
+	_, err = splitFields(`authors 'name:TEXT`)
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("splitFields() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	dir := t.TempDir()
+	*outputDirFlag = filepath.Join(dir, "out")
+	defer func() { *outputDirFlag = "" }()
+	spec := filepath.Join(dir, "spec.txt")
+	if err := os.WriteFile(spec, []byte("authors @id name@text\nbook/books @id title@text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	written, err := regenerate(spec, nil)
+	if err != nil {
+		t.Fatalf("regenerate() returned error: %v", err)
+	}
+
+	if err := os.WriteFile(spec, []byte("authors @id name@text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := regenerate(spec, written); err != nil {
+		t.Fatalf("regenerate() returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out", "query", "books.sql")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("regenerate() kept the queries of a removed table: %v", err)
+	}
+	schema, err := os.ReadFile(filepath.Join(dir, "out", "schema.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(schema), "CREATE TABLE"); n != 1 {
+		t.Errorf("regenerate() wrote schema with %d tables, want 1:\n%s", n, schema)
+	}
+}
+
+func TestReadEngines(t *testing.T) {
+	config := "version: \"2\"\nsql:\n  - engine: \"postgresql\"\n    schema: \"schema.sql\"\n  - engine: 'postgresql'\n  - engine: mysql\n"
+	got, err := readEngines(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("readEngines() returned error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"postgresql", "mysql"}, got); diff != "" {
+		t.Errorf("readEngines() returned wrong engines: diff -want +got\n%s", diff)
+	}
+}
+
+func TestSetEngine(t *testing.T) {
+	defer func() { *dialectFlag = "sqlite" }()
+	if err := setEngine("postgresql"); err != nil {
+		t.Fatalf("setEngine() returned error: %v", err)
+	}
+	if *dialectFlag != "postgres" {
+		t.Errorf("setEngine() set -dialect %s, want postgres", *dialectFlag)
+	}
+	err := setEngine("postgres")
+	if diff := cmp.Diff(errBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("setEngine() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestDetectEngine(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]struct {
+		config string
+		want   string
+		err    bool
+	}{
+		"single":  {config: "sql:\n  - engine: \"postgresql\"\n", want: "postgres"},
+		"several": {config: "sql:\n  - engine: sqlite\n  - engine: mysql\n"},
+		"unknown": {config: "sql:\n  - engine: oracle\n", err: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := detectEngine(path)
+			if (err != nil) != tt.err {
+				t.Fatalf("detectEngine() returned error %v, want error %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("detectEngine() returned dialect %q, want %q", got, tt.want)
+			}
+		})
+	}
+	got, err := detectEngine(filepath.Join(dir, "missing.yaml"))
+	if err != nil || got != "" {
+		t.Errorf("detectEngine() of missing file returned (%q, %v), want no dialect and no error", got, err)
+	}
+}
+
+func TestRegenerateKeepsExistingContent(t *testing.T) {
+	dir := t.TempDir()
+	*outputDirFlag = filepath.Join(dir, "out")
+	defer func() { *outputDirFlag = "" }()
+	schemaFile := filepath.Join(dir, "out", "schema.sql")
+	queriesFile := filepath.Join(dir, "out", "query", "authors.sql")
+	existing := map[string]string{
+		schemaFile:  "CREATE TABLE users (\n  id INTEGER PRIMARY KEY\n);\n",
+		queriesFile: "-- name: CountAuthors :one\nSELECT count(*) FROM authors;\n",
+	}
+	if err := os.MkdirAll(filepath.Dir(queriesFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range existing {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spec := filepath.Join(dir, "spec.txt")
+	if err := os.WriteFile(spec, []byte("authors @id name@text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var written map[string]string
+	for i := 0; i < 2; i++ {
+		var err error
+		written, err = regenerate(spec, written)
+		if err != nil {
+			t.Fatalf("regenerate() returned error: %v", err)
+		}
+	}
+	for name, content := range existing {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(got), content) {
+			t.Errorf("regenerate() did not keep the content of %s:\n%s", name, got)
+		}
+	}
+	schema, err := os.ReadFile(schemaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(schema), "CREATE TABLE"); n != 2 {
+		t.Errorf("regenerate() wrote schema with %d tables, want 2:\n%s", n, schema)
+	}
+	queries, err := os.ReadFile(queriesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(queries), "-- name: CreateAuthor "); n != 1 {
+		t.Errorf("regenerate() wrote %d CreateAuthor queries, want 1:\n%s", n, queries)
+	}
+}
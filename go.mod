@@ -2,4 +2,4 @@ module github.com/ngrash/sqlcup
 
 go 1.18
 
-require github.com/google/go-cmp v0.5.9 // indirect
+require github.com/google/go-cmp v0.5.9
@@ -0,0 +1,808 @@
+// Package sqlcup generates CREATE TABLE statements and basic CRUD queries for sqlc (https://sqlc.dev).
+package sqlcup
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SoftDeleteColumn is the name of the column that marks a row as deleted when using Args.SoftDelete.
+const SoftDeleteColumn = "deleted_at"
+
+// ErrBadArgument is wrapped by all errors caused by invalid Args.
+var ErrBadArgument = errors.New("bad argument")
+
+// Column is a single column of the generated table.
+type Column struct {
+	Name       string
+	Type       string
+	Constraint string
+	ID         bool
+	Unique     bool
+	// ReadOnly columns are managed by the database and omitted from INSERT statements.
+	// They are also omitted from UPDATE statements unless UpdateValue is set.
+	ReadOnly bool
+	// UpdateValue is an SQL expression assigned to the column in UPDATE statements instead of a parameter.
+	UpdateValue string
+	// Enum contains the values of an enumerated column. Unless Type is set, the type depends on the dialect:
+	// MySQL uses an ENUM type, PostgreSQL a type named <table>_<column> that is created before the table,
+	// and SQLite uses TEXT with a CHECK constraint.
+	Enum []string
+	// Comment documents the column in the schema.
+	Comment string
+	// Nullable marks a column that may be NULL for NullableHints.
+	Nullable bool
+}
+
+// Dialect is the SQL dialect of the generated statements.
+type Dialect uint8
+
+const (
+	DialectSQLite Dialect = iota
+	DialectPostgres
+	DialectMySQL
+)
+
+// PlaceholderStyle determines how bind parameters are rendered.
+type PlaceholderStyle uint8
+
+const (
+	// PlaceholderDefault uses PlaceholderDollar for PostgreSQL and PlaceholderQuestion for all other dialects.
+	PlaceholderDefault PlaceholderStyle = iota
+	PlaceholderQuestion
+	PlaceholderDollar
+)
+
+// renderPlaceholder returns the placeholder for the nth (1-based) bind parameter of a statement.
+func (s PlaceholderStyle) renderPlaceholder(n int) string {
+	if s == PlaceholderDollar {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// placeholders renders the bind parameters of a single SQL statement.
+type placeholders struct {
+	style PlaceholderStyle
+	named bool
+	n     int
+}
+
+// next returns the placeholder for the next bind parameter.
+func (p *placeholders) next() string {
+	p.n++
+	return p.style.renderPlaceholder(p.n)
+}
+
+// arg returns the placeholder for the next bind parameter, or a sqlc.arg() named parameter if p is named.
+func (p *placeholders) arg(name string) string {
+	if p.named {
+		return "sqlc.arg(" + name + ")"
+	}
+	return p.next()
+}
+
+// QueryNames contains the names of the basic queries as they appear in sqlc annotations.
+// Empty names default to Get<Singular>, List<Plural>, Create<Singular>, Delete<Singular> and Update<Singular>.
+type QueryNames struct {
+	Get    string
+	List   string
+	Create string
+	Delete string
+	Update string
+}
+
+// BasicQuery is a bit set of the basic queries.
+type BasicQuery uint8
+
+const (
+	// QueryGet is the Get<Singular> query and the <Singular>Exists query.
+	QueryGet BasicQuery = 1 << iota
+	// QueryList is the List<Plural> query and the Count<Plural> query.
+	QueryList
+	QueryCreate
+	QueryUpdate
+	QueryDelete
+)
+
+// Args describes the table and the queries to generate.
+type Args struct {
+	// Table is the name of the table.
+	Table string
+	// SchemaName qualifies the table and its types in the schema and the queries, e.g. "inventory".
+	SchemaName string
+	// SingularEntity and PluralEntity are used in query names, e.g. "Author" and "Authors".
+	SingularEntity string
+	PluralEntity   string
+	// View generates queries for an existing, read-only view named Table.
+	// No schema and only the queries that read rows are generated.
+	View bool
+	// TableComment documents the table in the schema.
+	TableComment string
+	// Columns are the columns of the table in the order they appear in the schema.
+	// Multiple ID columns form a composite primary key.
+	Columns []Column
+
+	Dialect          Dialect
+	PlaceholderStyle PlaceholderStyle
+	Names            QueryNames
+	// OmitQueries contains the basic queries that are not generated.
+	// Queries that are enabled explicitly, like those for FilterBy or PartialUpdates, are not affected.
+	OmitQueries BasicQuery
+	// CreateKind and UpdateKind are the sqlc query kinds of the INSERT and UPDATE statements
+	// without the leading colon, e.g. "exec". They default to "one" if the statement returns
+	// the affected row and to "execresult" or "exec" respectively otherwise.
+	CreateKind string
+	UpdateKind string
+
+	NoExistsClause bool
+	WithDrop       bool
+	// OrderBy is a comma-separated list of known columns, each optionally followed by ASC or DESC.
+	// It applies to all list queries without an entry in ListOrderBy.
+	OrderBy string
+	// ListOrderBy overrides OrderBy for single list queries. It is keyed by the name of the FilterBy column,
+	// or by the empty string for the unfiltered list query.
+	ListOrderBy       map[string]string
+	NoReturningClause bool
+	// NoCreateReturning omits the RETURNING clause of the Create<Singular> query.
+	NoCreateReturning bool
+	NoCount           bool
+	Paginate          bool
+	// NamedParams renders the filter and pagination parameters of list queries as sqlc.arg() named parameters,
+	// e.g. sqlc.arg(limit), so that sqlc derives readable parameter names from them.
+	NamedParams bool
+	// Timestamps adds created_at and updated_at columns.
+	// Like the SoftDeleteColumn, they are of type DATETIME or TIMESTAMPTZ for PostgreSQL.
+	Timestamps bool
+	// SoftDelete adds a SoftDeleteColumn and marks rows as deleted instead of deleting them.
+	SoftDelete     bool
+	PartialUpdates bool
+	// CoalesceUpdate keeps the value of a column in the Update<Singular> query if its parameter is NULL.
+	CoalesceUpdate bool
+	// Keyset adds a List<Plural>After query that selects a page of rows after the given id for keyset pagination.
+	// It requires a single id column.
+	Keyset bool
+	// GetManyByID adds a List<Plural>ByIDs query that selects all rows with one of the given ids.
+	// It requires a single id column.
+	GetManyByID bool
+	// RowidGet adds a Get<Singular>ByRowid query that selects a row and its rowid by the implicit rowid.
+	// It is only supported by DialectSQLite.
+	RowidGet bool
+	// ForUpdate adds a Get<Singular>ForUpdate query that locks the selected row.
+	// It is not supported by DialectSQLite.
+	ForUpdate bool
+	// WithTruncate adds a DeleteAll<Plural> query that deletes all rows of the table.
+	WithTruncate bool
+	// BatchInsert adds a Create<Plural> query that inserts many rows at once.
+	BatchInsert bool
+	Upsert      bool
+	// ConflictColumns are the conflict target of the upsert statement.
+	// They default to the ID columns or else the first unique column.
+	ConflictColumns []string
+	// Indent is the indentation of column lists and assignments. It defaults to two spaces.
+	Indent string
+	// MaxLineWidth wraps the column and value lists of INSERT statements to lines of at most MaxLineWidth bytes.
+	// Zero means no limit.
+	MaxLineWidth int
+	// ExplicitColumns lists all columns in SELECT statements instead of '*'.
+	ExplicitColumns bool
+	// Returning contains the columns returned by INSERT and UPDATE statements instead of all columns.
+	Returning []string
+	// QuoteIdentifiers quotes all table and column names in the schema and the queries.
+	QuoteIdentifiers bool
+	// LowercaseKeywords renders SQL keywords in lowercase, including those of the constraints generated by sqlcup.
+	// Column types and the constraints of Columns are not affected.
+	LowercaseKeywords bool
+	// AlignConstraints pads the constraints in the schema so that the separating commas line up.
+	AlignConstraints bool
+	// NullableHints adds a '-- nullable' comment to the schema lines of Nullable columns,
+	// which makes it easier to tell the columns apart that sqlc maps to sql.Null* types or pointers.
+	NullableHints bool
+	// UniqueAsIndex creates a unique index for each unique column instead of a UNIQUE column constraint.
+	// It also applies to UniqueConstraints.
+	UniqueAsIndex bool
+	// UniqueConstraints contains the columns of each UNIQUE table constraint.
+	UniqueConstraints [][]string
+	// Indexes contains the columns of each index to create after the table.
+	Indexes [][]string
+	// FilterBy contains the names of the columns to generate List<Plural>By<Column> queries for.
+	FilterBy []string
+	// Acronyms are the words that are written in uppercase when a column name becomes part of a query name,
+	// e.g. "ID" for GetAuthorByUserID. See UpperCamelCaseAcronyms.
+	Acronyms []string
+}
+
+// Query is a single generated sqlc query.
+type Query struct {
+	// Name is the name of the query in its sqlc annotation.
+	Name string
+	// Kind is the command of the sqlc annotation without the leading colon, e.g. "one" or "exec".
+	Kind string
+	// Text is the complete query including its annotation.
+	Text string
+}
+
+// Generate returns the CREATE TABLE statement and the queries for args.
+// The queries are separated by an empty line.
+func Generate(args Args) (schema, queries string, err error) {
+	schema, err = GenerateSchema(args)
+	if err != nil {
+		return "", "", err
+	}
+	qs, err := GenerateQueries(args)
+	if err != nil {
+		return "", "", err
+	}
+	var texts []string
+	for _, q := range qs {
+		texts = append(texts, q.Text)
+	}
+	return schema, strings.Join(texts, "\n\n"), nil
+}
+
+// GenerateSchema returns the CREATE TABLE statement for args.
+// It returns an empty string for a View.
+func GenerateSchema(args Args) (string, error) {
+	a, err := prepare(args)
+	if err != nil {
+		return "", err
+	}
+	if a.View {
+		return "", nil
+	}
+	b := &strings.Builder{}
+	writeSchema(b, a)
+	return b.String(), nil
+}
+
+// GenerateQueries returns the queries for args in the order they appear in the output.
+func GenerateQueries(args Args) ([]Query, error) {
+	a, err := prepare(args)
+	if err != nil {
+		return nil, err
+	}
+	var queries []Query
+	for _, write := range queryWriters(a) {
+		queries = append(queries, renderQuery(write, a))
+	}
+	return queries, nil
+}
+
+// GenerateFixtures returns n INSERT statements that fill the table of args with sample rows.
+// The statements are separated by an empty line.
+func GenerateFixtures(args Args, n int) (string, error) {
+	a, err := prepare(args)
+	if err != nil {
+		return "", err
+	}
+	if a.View {
+		return "", fmt.Errorf("%w: views are read-only", ErrBadArgument)
+	}
+	var fixtures []string
+	for i := 1; i <= n; i++ {
+		b := &strings.Builder{}
+		writeFixture(b, a, i)
+		fixtures = append(fixtures, b.String())
+	}
+	return strings.Join(fixtures, "\n\n"), nil
+}
+
+// Validate reports whether args can be generated.
+// It returns the error that Generate would return.
+func Validate(args Args) error {
+	_, err := prepare(args)
+	return err
+}
+
+// prepare returns a copy of args with the generated columns added and defaults applied.
+func prepare(args Args) (*Args, error) {
+	if args.Table == "" {
+		return nil, fmt.Errorf("%w: missing table name", ErrBadArgument)
+	}
+	a := args
+	a.Columns = append([]Column(nil), args.Columns...)
+	for i, col := range a.Columns {
+		if len(col.Enum) == 0 || col.Type != "" {
+			continue
+		}
+		values := enumValues(col.Enum)
+		switch a.Dialect {
+		case DialectMySQL:
+			a.Columns[i].Type = "ENUM(" + values + ")"
+		case DialectPostgres:
+			a.Columns[i].Type = a.enumType(col)
+		default:
+			a.Columns[i].Type = "TEXT"
+			a.Columns[i].Constraint = strings.TrimSpace(col.Constraint + " " + a.kw("CHECK") + " (" + a.quoteIdent(col.Name) + " " + a.kw("IN") + " (" + values + "))")
+		}
+	}
+	if len(a.idColumns()) > 1 {
+		for i, col := range a.Columns {
+			if col.ID {
+				a.Columns[i] = a.compositeKeyColumn(col)
+			}
+		}
+	}
+	if a.Timestamps {
+		a.Columns = append(a.Columns, Column{
+			Name:       "created_at",
+			Type:       a.timestampType(),
+			Constraint: a.kw("NOT NULL DEFAULT CURRENT_TIMESTAMP"),
+			ReadOnly:   true,
+		}, Column{
+			Name:        "updated_at",
+			Type:        a.timestampType(),
+			Constraint:  a.kw("NOT NULL DEFAULT CURRENT_TIMESTAMP"),
+			ReadOnly:    true,
+			UpdateValue: a.kw("CURRENT_TIMESTAMP"),
+		})
+	}
+	if a.SoftDelete {
+		a.Columns = append(a.Columns, Column{
+			Name:     SoftDeleteColumn,
+			Type:     a.timestampType(),
+			ReadOnly: true,
+			Nullable: true,
+		})
+	}
+
+	if len(a.Columns) == 0 {
+		return nil, fmt.Errorf("%w: at least one column required", ErrBadArgument)
+	}
+	seen := make(map[string]bool)
+	for _, col := range a.Columns {
+		// Unquoted identifiers are case-insensitive.
+		if seen[strings.ToLower(col.Name)] {
+			return nil, fmt.Errorf("%w: duplicate column '%s'", ErrBadArgument, col.Name)
+		}
+		seen[strings.ToLower(col.Name)] = true
+	}
+
+	for _, name := range []struct {
+		dst      *string
+		fallback string
+	}{
+		{&a.Names.Get, "Get" + a.SingularEntity},
+		{&a.Names.List, "List" + a.PluralEntity},
+		{&a.Names.Create, "Create" + a.SingularEntity},
+		{&a.Names.Delete, "Delete" + a.SingularEntity},
+		{&a.Names.Update, "Update" + a.SingularEntity},
+	} {
+		if *name.dst == "" {
+			*name.dst = name.fallback
+		}
+	}
+
+	if a.Indent == "" {
+		a.Indent = "  "
+	}
+	if a.PlaceholderStyle == PlaceholderDefault {
+		// PostgreSQL uses numbered parameters ($1, $2, ...), all other dialects use '?'.
+		if a.Dialect == DialectPostgres {
+			a.PlaceholderStyle = PlaceholderDollar
+		} else {
+			a.PlaceholderStyle = PlaceholderQuestion
+		}
+	}
+	if a.CreateKind == "" {
+		// MySQL does not support RETURNING, but :execresult gives access to the id of the inserted row.
+		switch {
+		case a.NoCreateReturning:
+			a.CreateKind = "exec"
+		case a.Dialect != DialectMySQL:
+			a.CreateKind = "one"
+		default:
+			a.CreateKind = "execresult"
+		}
+	}
+	if a.UpdateKind == "" {
+		if a.returning() {
+			a.UpdateKind = "one"
+		} else {
+			a.UpdateKind = "exec"
+		}
+	}
+
+	if a.View && (a.Upsert || a.BatchInsert || a.WithTruncate || a.PartialUpdates) {
+		return nil, fmt.Errorf("%w: views are read-only", ErrBadArgument)
+	}
+	if a.Keyset && len(a.idColumns()) != 1 {
+		return nil, fmt.Errorf("%w: keyset pagination requires a single id column", ErrBadArgument)
+	}
+	if a.GetManyByID && len(a.idColumns()) != 1 {
+		return nil, fmt.Errorf("%w: selecting many rows by id requires a single id column", ErrBadArgument)
+	}
+	if a.RowidGet && (a.Dialect != DialectSQLite || a.View) {
+		return nil, fmt.Errorf("%w: only SQLite tables have a rowid", ErrBadArgument)
+	}
+	if a.ForUpdate && a.Dialect == DialectSQLite {
+		return nil, fmt.Errorf("%w: SQLite does not support SELECT ... FOR UPDATE", ErrBadArgument)
+	}
+	if err := a.checkOrderBy(a.OrderBy); err != nil {
+		return nil, err
+	}
+	for filter, orderBy := range a.ListOrderBy {
+		if filter != "" && !a.hasFilter(filter) {
+			return nil, fmt.Errorf("%w: ORDER BY for unknown list query by '%s'", ErrBadArgument, filter)
+		}
+		if err := a.checkOrderBy(orderBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(a.Returning) > 0 && a.NoReturningClause {
+		return nil, fmt.Errorf("%w: RETURNING columns without RETURNING clause", ErrBadArgument)
+	}
+	if len(a.Returning) > 0 && a.Dialect == DialectMySQL {
+		return nil, fmt.Errorf("%w: MySQL does not support RETURNING", ErrBadArgument)
+	}
+	for _, name := range a.Returning {
+		if !a.hasColumn(name) {
+			return nil, fmt.Errorf("%w: no such RETURNING column '%s'", ErrBadArgument, name)
+		}
+	}
+
+	var unknown []string
+	for _, name := range a.FilterBy {
+		if !a.hasColumn(name) {
+			unknown = append(unknown, "'"+name+"'")
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: unknown filter columns %s", ErrBadArgument, strings.Join(unknown, ", "))
+	}
+	for _, cols := range a.UniqueConstraints {
+		if len(cols) == 0 {
+			return nil, fmt.Errorf("%w: unique constraint without columns", ErrBadArgument)
+		}
+		for _, name := range cols {
+			if !a.hasColumn(name) {
+				return nil, fmt.Errorf("%w: no such unique column '%s'", ErrBadArgument, name)
+			}
+		}
+	}
+	for _, cols := range a.Indexes {
+		if len(cols) == 0 {
+			return nil, fmt.Errorf("%w: index without columns", ErrBadArgument)
+		}
+		for _, name := range cols {
+			if !a.hasColumn(name) {
+				return nil, fmt.Errorf("%w: no such index column '%s'", ErrBadArgument, name)
+			}
+		}
+	}
+
+	if a.Upsert {
+		if len(a.ConflictColumns) == 0 {
+			a.ConflictColumns = a.defaultConflictColumns()
+		}
+		if len(a.ConflictColumns) == 0 {
+			return nil, fmt.Errorf("%w: upsert requires an id column, a unique column or conflict columns", ErrBadArgument)
+		}
+		for _, name := range a.ConflictColumns {
+			if !a.hasColumn(name) {
+				return nil, fmt.Errorf("%w: no such conflict column '%s'", ErrBadArgument, name)
+			}
+		}
+	}
+	return &a, nil
+}
+
+// compositeKeyPattern matches the parts of a column constraint that only apply to a single-column primary key.
+var compositeKeyPattern = regexp.MustCompile(`(?i)\b(PRIMARY\s+KEY|AUTO_?INCREMENT)\b`)
+
+// compositeKeyColumn turns col into a member of a composite primary key.
+// The key itself is defined by a table constraint, so the PRIMARY KEY column constraint is removed.
+// Auto-incrementing columns are replaced by plain integer columns.
+func (args *Args) compositeKeyColumn(col Column) Column {
+	col.Constraint = strings.Join(strings.Fields(compositeKeyPattern.ReplaceAllString(col.Constraint, "")), " ")
+	if !strings.Contains(strings.ToUpper(col.Constraint), "NOT NULL") {
+		col.Constraint = strings.TrimSpace(args.kw("NOT NULL ") + col.Constraint)
+	}
+	switch strings.ToUpper(col.Type) {
+	case "SERIAL":
+		col.Type = "INTEGER"
+	case "BIGSERIAL":
+		col.Type = "BIGINT"
+	case "SMALLSERIAL":
+		col.Type = "SMALLINT"
+	}
+	return col
+}
+
+// enumType returns the name of the PostgreSQL type of the enumerated column col.
+func (args *Args) enumType(col Column) string {
+	name := strings.NewReplacer(`"`, "", "`", "").Replace(args.Table + "_" + col.Name)
+	if args.SchemaName != "" {
+		return args.schemaIdentifier(args.SchemaName) + "." + name
+	}
+	return name
+}
+
+// enumValues returns the values of an enumerated column as a list of SQL string literals.
+func enumValues(values []string) string {
+	var literals []string
+	for _, v := range values {
+		literals = append(literals, stringLiteral(v))
+	}
+	return strings.Join(literals, ", ")
+}
+
+// stringLiteral returns s as an SQL string literal.
+func stringLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// timestampType returns the type of the columns added by Timestamps and SoftDelete.
+// PostgreSQL has no DATETIME type.
+func (args *Args) timestampType() string {
+	if args.Dialect == DialectPostgres {
+		return "TIMESTAMPTZ"
+	}
+	return "DATETIME"
+}
+
+// checkOrderBy returns an error wrapping ErrBadArgument unless each comma-separated term of orderBy
+// is a known column optionally followed by ASC or DESC.
+func (args *Args) checkOrderBy(orderBy string) error {
+	if orderBy == "" {
+		return nil
+	}
+	for _, term := range strings.Split(orderBy, ",") {
+		fields := strings.Fields(term)
+		if len(fields) == 0 || len(fields) > 2 {
+			return fmt.Errorf("%w: invalid ORDER BY term '%s', expected '<column>[ ASC|DESC]'", ErrBadArgument, strings.TrimSpace(term))
+		}
+		if len(fields) == 2 {
+			if dir := strings.ToUpper(fields[1]); dir != "ASC" && dir != "DESC" {
+				return fmt.Errorf("%w: invalid ORDER BY direction '%s', expected ASC or DESC", ErrBadArgument, fields[1])
+			}
+		}
+		if !args.hasColumn(fields[0]) {
+			return fmt.Errorf("%w: no such ORDER BY column '%s'", ErrBadArgument, fields[0])
+		}
+	}
+	return nil
+}
+
+// hasFilter reports whether FilterBy contains name.
+func (args *Args) hasFilter(name string) bool {
+	for _, filter := range args.FilterBy {
+		if filter == name {
+			return true
+		}
+	}
+	return false
+}
+
+// listOrderBy returns the ORDER BY clause of the list query filtered by the named column,
+// or of the unfiltered list query if filter is empty.
+func (args *Args) listOrderBy(filter string) string {
+	if orderBy, ok := args.ListOrderBy[filter]; ok {
+		return orderBy
+	}
+	return args.OrderBy
+}
+
+// IsQuotedIdentifier reports whether name is enclosed in double quotes or backticks.
+func IsQuotedIdentifier(name string) bool {
+	if len(name) < 2 {
+		return false
+	}
+	first, last := name[0], name[len(name)-1]
+	return first == last && (first == '"' || first == '`')
+}
+
+// QuoteIdentifier quotes name for dialect d.
+func QuoteIdentifier(name string, d Dialect) string {
+	if d == DialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// placeholders returns a new placeholder sequence for a statement in the dialect of args.
+func (args *Args) placeholders() *placeholders {
+	return &placeholders{style: args.PlaceholderStyle, named: args.NamedParams}
+}
+
+// idColumns returns the columns that identify a row.
+func (args *Args) idColumns() []Column {
+	var cols []Column
+	for _, col := range args.Columns {
+		if col.ID {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// nonIDColumns returns the columns that do not identify a row.
+func (args *Args) nonIDColumns() []Column {
+	var cols []Column
+	for _, col := range args.Columns {
+		if !col.ID {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// hasColumn reports whether args defines a column with the given name.
+func (args *Args) hasColumn(name string) bool {
+	for _, col := range args.Columns {
+		if col.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultConflictColumns returns the names of the id columns or else the name of the first unique column.
+// It returns nil if there is neither.
+func (args *Args) defaultConflictColumns() []string {
+	if names := args.idColumnNames(); len(names) > 0 {
+		return names
+	}
+	for _, col := range args.Columns {
+		if col.Unique {
+			return []string{col.Name}
+		}
+	}
+	return nil
+}
+
+// idColumnNames returns the names of the columns that identify a row.
+func (args *Args) idColumnNames() []string {
+	var names []string
+	for _, col := range args.idColumns() {
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+// idCondition returns the WHERE condition that matches a single row by its id columns.
+func (args *Args) idCondition(p *placeholders) string {
+	var conds []string
+	for _, col := range args.idColumns() {
+		conds = append(conds, fmt.Sprintf("%s = %s", args.quoteIdent(col.Name), p.next()))
+	}
+	return strings.Join(conds, args.kw(" AND "))
+}
+
+// schemaIdentifier returns name as it appears in the schema.
+// MySQL identifiers are quoted with backticks so that reserved words can be used as names.
+func (args *Args) schemaIdentifier(name string) string {
+	if args.Dialect == DialectMySQL && !IsQuotedIdentifier(name) {
+		return QuoteIdentifier(name, args.Dialect)
+	}
+	return args.quoteIdent(name)
+}
+
+// schemaTable returns the table name as it appears in the schema, qualified by SchemaName.
+func (args *Args) schemaTable() string {
+	if args.SchemaName != "" {
+		return args.schemaIdentifier(args.SchemaName) + "." + args.schemaIdentifier(args.Table)
+	}
+	return args.schemaIdentifier(args.Table)
+}
+
+// queryTable returns the table name as it appears in queries, qualified by SchemaName.
+func (args *Args) queryTable() string {
+	if args.SchemaName != "" {
+		return args.quoteIdent(args.SchemaName) + "." + args.quoteIdent(args.Table)
+	}
+	return args.quoteIdent(args.Table)
+}
+
+// quoteIdent returns name as it appears in queries.
+// With QuoteIdentifiers, names that are not quoted yet are quoted for the dialect of args.
+func (args *Args) quoteIdent(name string) string {
+	if !args.QuoteIdentifiers || IsQuotedIdentifier(name) {
+		return name
+	}
+	return QuoteIdentifier(name, args.Dialect)
+}
+
+// quoteOrderBy returns orderBy with each column quoted by quoteIdent.
+// orderBy must have been validated by checkOrderBy.
+func (args *Args) quoteOrderBy(orderBy string) string {
+	if !args.QuoteIdentifiers {
+		return orderBy
+	}
+	var terms []string
+	for _, term := range strings.Split(orderBy, ",") {
+		fields := strings.Fields(term)
+		fields[0] = args.quoteIdent(fields[0])
+		terms = append(terms, strings.Join(fields, " "))
+	}
+	return strings.Join(terms, ", ")
+}
+
+// returning reports whether INSERT and UPDATE statements return the affected row.
+// MySQL does not support RETURNING.
+func (args *Args) returning() bool {
+	return !args.NoReturningClause && args.Dialect != DialectMySQL
+}
+
+// readCondition returns the WHERE condition that matches cond when reading rows.
+// With SoftDelete, rows marked as deleted are excluded.
+func (args *Args) readCondition(cond string) string {
+	if !args.SoftDelete {
+		return cond
+	}
+	if cond == "" {
+		return args.quoteIdent(SoftDeleteColumn) + args.kw(" IS NULL")
+	}
+	return cond + args.kw(" AND ") + args.quoteIdent(SoftDeleteColumn) + args.kw(" IS NULL")
+}
+
+// insertColumns returns the columns that are set by INSERT statements.
+// Unlike a single id column, the columns of a composite primary key are never generated by the database.
+func (args *Args) insertColumns() []Column {
+	var cols []Column
+	if ids := args.idColumns(); len(ids) > 1 {
+		cols = append(cols, ids...)
+	}
+	for _, col := range args.nonIDColumns() {
+		if !col.ReadOnly {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// updateColumns returns the columns that are set by UPDATE statements.
+func (args *Args) updateColumns() []Column {
+	var cols []Column
+	for _, col := range args.nonIDColumns() {
+		if !col.ReadOnly || col.UpdateValue != "" {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// UpperCamelCase converts a string like "zipcode_imports" to "ZipcodeImports".
+// Empty parts are dropped, so "_data" becomes "Data". Parts that do not start with a letter are kept as they are.
+func UpperCamelCase(s string) string {
+	return UpperCamelCaseAcronyms(s, nil)
+}
+
+// UpperCamelCaseAcronyms is like UpperCamelCase, but writes the parts of s that equal one of acronyms
+// regardless of case as that acronym, e.g. "api_keys" becomes "APIKeys" if acronyms contains "API".
+func UpperCamelCaseAcronyms(s string, acronyms []string) string {
+	b := strings.Builder{}
+	for _, p := range strings.Split(s, "_") {
+		b.WriteString(capitalizeAcronym(p, acronyms))
+	}
+	return b.String()
+}
+
+// upperCamelCase converts name to upper camel case for use in query names.
+func (args *Args) upperCamelCase(name string) string {
+	return UpperCamelCaseAcronyms(name, args.Acronyms)
+}
+
+// capitalizeAcronym returns the acronym that equals s regardless of case, or else s capitalized.
+func capitalizeAcronym(s string, acronyms []string) string {
+	for _, acronym := range acronyms {
+		if strings.EqualFold(s, acronym) {
+			return acronym
+		}
+	}
+	return capitalize(s)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return ""
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
@@ -0,0 +1,712 @@
+package sqlcup
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"strings"
+	"testing"
+)
+
+var authorArgs = Args{
+	Table:          "authors",
+	SingularEntity: "Author",
+	PluralEntity:   "Authors",
+	Columns: []Column{
+		{Name: "id", Type: "INTEGER", Constraint: "PRIMARY KEY", ID: true},
+		{Name: "name", Type: "TEXT", Constraint: "NOT NULL"},
+	},
+}
+
+func TestGenerate(t *testing.T) {
+	schema, queries, err := Generate(authorArgs)
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	wantSchema := `CREATE TABLE IF NOT EXISTS authors (
+  id   INTEGER PRIMARY KEY,
+  name TEXT    NOT NULL
+);`
+	if diff := cmp.Diff(wantSchema, schema); diff != "" {
+		t.Errorf("Generate() returned wrong schema: diff -want +got\n%s", diff)
+	}
+	wantQueries := `-- name: GetAuthor :one
+SELECT * FROM authors
+WHERE id = ? LIMIT 1;
+
+-- name: AuthorExists :one
+SELECT EXISTS(SELECT 1 FROM authors WHERE id = ?);
+
+-- name: ListAuthors :many
+SELECT * FROM authors;
+
+-- name: CountAuthors :one
+SELECT COUNT(*) FROM authors;
+
+-- name: CreateAuthor :one
+INSERT INTO authors (
+  name
+) VALUES (
+  ?
+)
+RETURNING *;
+
+-- name: DeleteAuthor :exec
+DELETE FROM authors
+WHERE id = ?;
+
+-- name: UpdateAuthor :one
+UPDATE authors
+SET
+  name = ?
+WHERE id = ?
+RETURNING *;`
+	if diff := cmp.Diff(wantQueries, queries); diff != "" {
+		t.Errorf("Generate() returned wrong queries: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateQueriesDialect(t *testing.T) {
+	args := authorArgs
+	args.Dialect = DialectPostgres
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := "-- name: UpdateAuthor :one\nUPDATE authors\nSET\n  name = $1\nWHERE id = $2\nRETURNING *;"
+	if got := queries[len(queries)-1]; got.Name != "UpdateAuthor" || got.Kind != "one" || got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong last query: %+v", got)
+	}
+}
+
+func TestGenerateUpsertWithoutConflictTarget(t *testing.T) {
+	args := Args{
+		Table:   "notes",
+		Columns: []Column{{Name: "body", Type: "TEXT"}},
+		Upsert:  true,
+	}
+	_, _, err := Generate(args)
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Generate() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateFilterBy(t *testing.T) {
+	args := authorArgs
+	args.FilterBy = []string{"name"}
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := "-- name: ListAuthorsByName :many\nSELECT * FROM authors\nWHERE name = ?;"
+	if got := queries[4]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong filter query: %+v", got)
+	}
+
+	args.FilterBy = []string{"name", "email"}
+	_, err = GenerateQueries(args)
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("GenerateQueries() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateBatchInsert(t *testing.T) {
+	for d, kind := range map[Dialect]string{DialectSQLite: "batchexec", DialectPostgres: "copyfrom", DialectMySQL: "batchexec"} {
+		args := authorArgs
+		args.Dialect = d
+		args.BatchInsert = true
+		queries, err := GenerateQueries(args)
+		if err != nil {
+			t.Fatalf("GenerateQueries() returned error: %v", err)
+		}
+		var found bool
+		for _, q := range queries {
+			if q.Name == "CreateAuthors" {
+				found = true
+				if q.Kind != kind {
+					t.Errorf("GenerateQueries() with dialect %d returned kind %s, want %s", d, q.Kind, kind)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("GenerateQueries() with dialect %d returned no CreateAuthors query", d)
+		}
+	}
+}
+
+var orderByTests = map[string]error{
+	"name":              nil,
+	"name DESC, id":     nil,
+	"name asc,id desc":  nil,
+	"email":             ErrBadArgument,
+	"name DOWN":         ErrBadArgument,
+	"name DESC NULLS":   ErrBadArgument,
+	"name,":             ErrBadArgument,
+	"name DESC, , id":   ErrBadArgument,
+	"name DESC, nameid": ErrBadArgument,
+}
+
+func TestValidateOrderBy(t *testing.T) {
+	for orderBy, want := range orderByTests {
+		t.Run(orderBy, func(t *testing.T) {
+			args := authorArgs
+			args.OrderBy = orderBy
+			err := Validate(args)
+			if diff := cmp.Diff(want, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Validate() with OrderBy '%s' returned wrong error: diff -want +got\n%s", orderBy, diff)
+			}
+		})
+	}
+}
+
+func TestGenerateSchemaIndexes(t *testing.T) {
+	args := authorArgs
+	args.Indexes = [][]string{{"name"}, {"name", "id"}}
+	schema, err := GenerateSchema(args)
+	if err != nil {
+		t.Fatalf("GenerateSchema() returned error: %v", err)
+	}
+	want := `CREATE TABLE IF NOT EXISTS authors (
+  id   INTEGER PRIMARY KEY,
+  name TEXT    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_authors_name ON authors (name);
+CREATE INDEX IF NOT EXISTS idx_authors_name_id ON authors (name, id);`
+	if diff := cmp.Diff(want, schema); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong schema: diff -want +got\n%s", diff)
+	}
+
+	args.Indexes = [][]string{{"email"}}
+	_, err = GenerateSchema(args)
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateSchemaCompositeKey(t *testing.T) {
+	args := Args{
+		Table:          "post_tags",
+		SingularEntity: "PostTag",
+		PluralEntity:   "PostTags",
+		Dialect:        DialectPostgres,
+		Columns: []Column{
+			{Name: "post_id", Type: "SERIAL", Constraint: "PRIMARY KEY", ID: true},
+			{Name: "tag_id", Type: "INTEGER", Constraint: "primary key references tags(id)", ID: true},
+		},
+	}
+	schema, err := GenerateSchema(args)
+	if err != nil {
+		t.Fatalf("GenerateSchema() returned error: %v", err)
+	}
+	want := `CREATE TABLE IF NOT EXISTS post_tags (
+  post_id INTEGER NOT NULL,
+  tag_id  INTEGER NOT NULL references tags(id),
+  PRIMARY KEY (post_id, tag_id)
+);`
+	if diff := cmp.Diff(want, schema); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong schema: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateSchemaUniqueConstraints(t *testing.T) {
+	args := authorArgs
+	args.UniqueConstraints = [][]string{{"name", "id"}}
+	schema, err := GenerateSchema(args)
+	if err != nil {
+		t.Fatalf("GenerateSchema() returned error: %v", err)
+	}
+	want := `CREATE TABLE IF NOT EXISTS authors (
+  id   INTEGER PRIMARY KEY,
+  name TEXT    NOT NULL,
+  UNIQUE (name, id)
+);`
+	if diff := cmp.Diff(want, schema); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong schema: diff -want +got\n%s", diff)
+	}
+
+	args.UniqueConstraints = [][]string{{"name", "email"}}
+	_, err = GenerateSchema(args)
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateSchemaNullableHints(t *testing.T) {
+	args := authorArgs
+	args.Columns = append(args.Columns, Column{Name: "bio", Type: "TEXT", Nullable: true})
+	args.NullableHints = true
+	schema, err := GenerateSchema(args)
+	if err != nil {
+		t.Fatalf("GenerateSchema() returned error: %v", err)
+	}
+	want := `CREATE TABLE IF NOT EXISTS authors (
+  id   INTEGER PRIMARY KEY,
+  name TEXT    NOT NULL,
+  bio  TEXT -- nullable
+);`
+	if diff := cmp.Diff(want, schema); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong schema: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateSchemaUniqueAsIndex(t *testing.T) {
+	args := authorArgs
+	args.Columns = append(args.Columns, Column{Name: "email", Type: "TEXT", Constraint: "NOT NULL UNIQUE", Unique: true})
+	args.UniqueAsIndex = true
+	schema, err := GenerateSchema(args)
+	if err != nil {
+		t.Fatalf("GenerateSchema() returned error: %v", err)
+	}
+	want := `CREATE TABLE IF NOT EXISTS authors (
+  id    INTEGER PRIMARY KEY,
+  name  TEXT    NOT NULL,
+  email TEXT    NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_authors_email ON authors (email);`
+	if diff := cmp.Diff(want, schema); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong schema: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateSchemaAlignConstraints(t *testing.T) {
+	args := authorArgs
+	args.Columns = append(args.Columns[:1:1], Column{Name: "bio", Type: "TEXT"}, args.Columns[1])
+	args.AlignConstraints = true
+	schema, err := GenerateSchema(args)
+	if err != nil {
+		t.Fatalf("GenerateSchema() returned error: %v", err)
+	}
+	want := `CREATE TABLE IF NOT EXISTS authors (
+  id   INTEGER PRIMARY KEY,
+  bio  TEXT               ,
+  name TEXT    NOT NULL
+);`
+	if diff := cmp.Diff(want, schema); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong schema: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateQueriesExplicitColumns(t *testing.T) {
+	args := authorArgs
+	args.ExplicitColumns = true
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := "-- name: GetAuthor :one\nSELECT id, name FROM authors\nWHERE id = ? LIMIT 1;"
+	if got := queries[0]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong get query: %+v", got)
+	}
+	for _, q := range queries {
+		if strings.Contains(q.Text, "RETURNING") && !strings.HasSuffix(q.Text, "\nRETURNING id, name;") {
+			t.Errorf("GenerateQueries() returned query %s with wrong RETURNING clause:\n%s", q.Name, q.Text)
+		}
+	}
+}
+
+func TestGenerateQuoteIdentifiers(t *testing.T) {
+	args := authorArgs
+	args.Dialect = DialectMySQL
+	args.QuoteIdentifiers = true
+	args.OrderBy = "name DESC, id"
+	schema, err := GenerateSchema(args)
+	if err != nil {
+		t.Fatalf("GenerateSchema() returned error: %v", err)
+	}
+	wantSchema := "CREATE TABLE IF NOT EXISTS `authors` (\n  `id`   INTEGER PRIMARY KEY,\n  `name` TEXT    NOT NULL\n);"
+	if diff := cmp.Diff(wantSchema, schema); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong schema: diff -want +got\n%s", diff)
+	}
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := "-- name: ListAuthors :many\nSELECT * FROM `authors`\nORDER BY `name` DESC, `id`;"
+	if got := queries[2]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong list query: %+v", got)
+	}
+	want = "-- name: UpdateAuthor :exec\nUPDATE `authors`\nSET\n  `name` = ?\nWHERE `id` = ?;"
+	if got := queries[len(queries)-1]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong update query: %+v", got)
+	}
+}
+
+func TestGenerateQueriesNamedParams(t *testing.T) {
+	args := authorArgs
+	args.Paginate = true
+	args.NamedParams = true
+	args.FilterBy = []string{"name"}
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := "-- name: ListAuthorsByName :many\nSELECT * FROM authors\nWHERE name = sqlc.arg(name)\nLIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);"
+	if got := queries[4]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong filter query: %+v", got)
+	}
+}
+
+var upperCamelCaseAcronymsTests = map[string]string{
+	"zipcode_imports": "ZipcodeImports",
+	"api_keys":        "APIKeys",
+	"user_id":         "UserID",
+	"idea":            "Idea",
+	"HTTP":            "HTTP",
+}
+
+func TestUpperCamelCaseAcronyms(t *testing.T) {
+	acronyms := []string{"ID", "API", "URL", "HTTP"}
+	for s, want := range upperCamelCaseAcronymsTests {
+		if got := UpperCamelCaseAcronyms(s, acronyms); got != want {
+			t.Errorf("UpperCamelCaseAcronyms(\"%s\") = %s, want %s", s, got, want)
+		}
+	}
+}
+
+func TestGenerateQueriesOmitQueries(t *testing.T) {
+	args := authorArgs
+	args.OmitQueries = QueryList | QueryDelete
+	args.FilterBy = []string{"name"}
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	var names []string
+	for _, q := range queries {
+		names = append(names, q.Name)
+	}
+	want := []string{"GetAuthor", "AuthorExists", "ListAuthorsByName", "CreateAuthor", "UpdateAuthor"}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("GenerateQueries() returned wrong queries: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateQueriesCoalesceUpdate(t *testing.T) {
+	args := authorArgs
+	args.CoalesceUpdate = true
+	args.PartialUpdates = true
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := "-- name: UpdateAuthor :one\nUPDATE authors\nSET\n  name = COALESCE(?, name)\nWHERE id = ?\nRETURNING *;"
+	if got := queries[len(queries)-2]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong update query: %+v", got)
+	}
+	want = "-- name: UpdateAuthorName :one\nUPDATE authors\nSET\n  name = ?\nWHERE id = ?\nRETURNING *;"
+	if got := queries[len(queries)-1]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong partial update query: %+v", got)
+	}
+}
+
+func TestGenerateQueriesMaxLineWidth(t *testing.T) {
+	args := authorArgs
+	args.Columns = append(args.Columns[:1:1], Column{Name: "first_name"}, Column{Name: "last_name"}, Column{Name: "email"})
+	args.MaxLineWidth = 24
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := "-- name: CreateAuthor :one\nINSERT INTO authors (\n  first_name, last_name,\n  email\n) VALUES (\n  ?, ?, ?\n)\nRETURNING *;"
+	if got := queries[4]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong create query: %+v", got)
+	}
+}
+
+func TestGenerateSchemaEnum(t *testing.T) {
+	args := Args{
+		Table:   "tasks",
+		Columns: []Column{{Name: "status", Constraint: "NOT NULL", Enum: []string{"open", "done"}}},
+	}
+	want := map[Dialect]string{
+		DialectSQLite:   "CREATE TABLE IF NOT EXISTS tasks (\n  status TEXT NOT NULL CHECK (status IN ('open', 'done'))\n);",
+		DialectPostgres: "CREATE TYPE tasks_status AS ENUM ('open', 'done');\nCREATE TABLE IF NOT EXISTS tasks (\n  status tasks_status NOT NULL\n);",
+		DialectMySQL:    "CREATE TABLE IF NOT EXISTS `tasks` (\n  `status` ENUM('open', 'done') NOT NULL\n);",
+	}
+	for d, want := range want {
+		args.Dialect = d
+		schema, err := GenerateSchema(args)
+		if err != nil {
+			t.Fatalf("GenerateSchema() returned error: %v", err)
+		}
+		if diff := cmp.Diff(want, schema); diff != "" {
+			t.Errorf("GenerateSchema() with dialect %d returned wrong schema: diff -want +got\n%s", d, diff)
+		}
+	}
+
+	args.Dialect = DialectSQLite
+	args.LowercaseKeywords = true
+	args.QuoteIdentifiers = true
+	schema, err := GenerateSchema(args)
+	if err != nil {
+		t.Fatalf("GenerateSchema() returned error: %v", err)
+	}
+	wantLower := "create table if not exists \"tasks\" (\n  \"status\" TEXT NOT NULL check (\"status\" in ('open', 'done'))\n);"
+	if diff := cmp.Diff(wantLower, schema); diff != "" {
+		t.Errorf("GenerateSchema() with lowercase keywords and quoted identifiers returned wrong schema: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateSchemaComments(t *testing.T) {
+	args := authorArgs
+	args.Columns = append(args.Columns[:1:1], Column{Name: "name", Type: "TEXT", Constraint: "NOT NULL", Comment: "Full name"})
+	args.TableComment = "Authors of books"
+	want := map[Dialect]string{
+		DialectSQLite: `-- Authors of books
+CREATE TABLE IF NOT EXISTS authors (
+  id   INTEGER PRIMARY KEY,
+  name TEXT    NOT NULL -- Full name
+);`,
+		DialectPostgres: `CREATE TABLE IF NOT EXISTS authors (
+  id   INTEGER PRIMARY KEY,
+  name TEXT    NOT NULL
+);
+
+COMMENT ON TABLE authors IS 'Authors of books';
+COMMENT ON COLUMN authors.name IS 'Full name';`,
+		DialectMySQL: "CREATE TABLE IF NOT EXISTS `authors` (\n  `id`   INTEGER PRIMARY KEY,\n  `name` TEXT    NOT NULL COMMENT 'Full name'\n) COMMENT='Authors of books';",
+	}
+	for d, want := range want {
+		args.Dialect = d
+		schema, err := GenerateSchema(args)
+		if err != nil {
+			t.Fatalf("GenerateSchema() returned error: %v", err)
+		}
+		if diff := cmp.Diff(want, schema); diff != "" {
+			t.Errorf("GenerateSchema() with dialect %d returned wrong schema: diff -want +got\n%s", d, diff)
+		}
+	}
+}
+
+func TestValidateNoColumns(t *testing.T) {
+	err := Validate(Args{Table: "authors"})
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Validate() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestValidateDuplicateColumns(t *testing.T) {
+	args := authorArgs
+	args.Columns = append(args.Columns, Column{Name: "created_at", Type: "DATETIME"})
+	args.Timestamps = true
+	err := Validate(args)
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Validate() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateSchemaName(t *testing.T) {
+	args := authorArgs
+	args.SchemaName = "library"
+	args.Indexes = [][]string{{"name"}}
+	schema, queries, err := Generate(args)
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	wantSchema := `CREATE TABLE IF NOT EXISTS library.authors (
+  id   INTEGER PRIMARY KEY,
+  name TEXT    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_authors_name ON library.authors (name);`
+	if diff := cmp.Diff(wantSchema, schema); diff != "" {
+		t.Errorf("Generate() returned wrong schema: diff -want +got\n%s", diff)
+	}
+	if !strings.HasPrefix(queries, "-- name: GetAuthor :one\nSELECT * FROM library.authors\n") {
+		t.Errorf("Generate() returned queries with unqualified table:\n%s", queries)
+	}
+}
+
+func TestGenerateSchemaTimestampsPostgres(t *testing.T) {
+	args := authorArgs
+	args.Dialect = DialectPostgres
+	args.Timestamps = true
+	schema, err := GenerateSchema(args)
+	if err != nil {
+		t.Fatalf("GenerateSchema() returned error: %v", err)
+	}
+	want := `CREATE TABLE IF NOT EXISTS authors (
+  id         INTEGER     PRIMARY KEY,
+  name       TEXT        NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`
+	if diff := cmp.Diff(want, schema); diff != "" {
+		t.Errorf("GenerateSchema() returned wrong schema: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateQueriesGetManyByID(t *testing.T) {
+	want := map[Dialect]string{
+		DialectSQLite:   "-- name: ListAuthorsByIDs :many\nSELECT * FROM authors\nWHERE id IN (sqlc.slice(ids));",
+		DialectPostgres: "-- name: ListAuthorsByIDs :many\nSELECT * FROM authors\nWHERE id = ANY(sqlc.arg(ids)::INTEGER[]);",
+	}
+	for d, want := range want {
+		args := authorArgs
+		args.Dialect = d
+		args.GetManyByID = true
+		queries, err := GenerateQueries(args)
+		if err != nil {
+			t.Fatalf("GenerateQueries() returned error: %v", err)
+		}
+		if got := queries[4]; got.Text != want {
+			t.Errorf("GenerateQueries() with dialect %d returned wrong query: %+v", d, got)
+		}
+	}
+
+	args := authorArgs
+	args.GetManyByID = true
+	args.OrderBy = "id"
+	args.ListOrderBy = map[string]string{"": "name DESC"}
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	wantOrdered := "-- name: ListAuthorsByIDs :many\nSELECT * FROM authors\nWHERE id IN (sqlc.slice(ids))\nORDER BY name DESC;"
+	if got := queries[4]; got.Text != wantOrdered {
+		t.Errorf("GenerateQueries() with ListOrderBy returned wrong query: %+v", got)
+	}
+
+	args = authorArgs
+	args.Columns = []Column{{Name: "name", Type: "TEXT"}}
+	args.GetManyByID = true
+	_, err = GenerateQueries(args)
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("GenerateQueries() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateQueriesRowidGet(t *testing.T) {
+	args := authorArgs
+	args.RowidGet = true
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := Query{
+		Name: "GetAuthorByRowid",
+		Kind: "one",
+		Text: "-- name: GetAuthorByRowid :one\nSELECT *, rowid FROM authors\nWHERE rowid = ? LIMIT 1;",
+	}
+	if diff := cmp.Diff(want, queries[2]); diff != "" {
+		t.Errorf("GenerateQueries() returned wrong query: diff -want +got\n%s", diff)
+	}
+
+	args.Dialect = DialectPostgres
+	_, err = GenerateQueries(args)
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("GenerateQueries() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateFixtures(t *testing.T) {
+	args := authorArgs
+	args.Columns = append(args.Columns, Column{Name: "age", Type: "INTEGER"}, Column{Name: "born", Type: "DATETIME"})
+	fixtures, err := GenerateFixtures(args, 2)
+	if err != nil {
+		t.Fatalf("GenerateFixtures() returned error: %v", err)
+	}
+	want := `INSERT INTO authors (
+  name, age, born
+) VALUES (
+  'name 1', 1, CURRENT_TIMESTAMP
+);
+
+INSERT INTO authors (
+  name, age, born
+) VALUES (
+  'name 2', 2, CURRENT_TIMESTAMP
+);`
+	if diff := cmp.Diff(want, fixtures); diff != "" {
+		t.Errorf("GenerateFixtures() returned wrong fixtures: diff -want +got\n%s", diff)
+	}
+}
+
+func TestGenerateQueriesKeyset(t *testing.T) {
+	args := authorArgs
+	args.Keyset = true
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := "-- name: ListAuthorsAfter :many\n-- The last parameter is LIMIT.\nSELECT * FROM authors\nWHERE id > ?\nORDER BY id\nLIMIT ?;"
+	if got := queries[4]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong query: %+v", got)
+	}
+
+	args.Columns = []Column{{Name: "name", Type: "TEXT"}}
+	_, err = GenerateQueries(args)
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("GenerateQueries() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
+
+func TestUpperCamelCase(t *testing.T) {
+	for s, want := range map[string]string{"zipcode_imports": "ZipcodeImports", "_data": "Data", "a__b": "AB", "2fa_codes": "2faCodes", "": ""} {
+		if got := UpperCamelCase(s); got != want {
+			t.Errorf("UpperCamelCase(\"%s\") = %s, want %s", s, got, want)
+		}
+	}
+}
+
+func TestGenerateQueriesReturning(t *testing.T) {
+	args := authorArgs
+	args.Returning = []string{"id"}
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	for _, q := range queries {
+		if strings.Contains(q.Text, "RETURNING") && !strings.HasSuffix(q.Text, "\nRETURNING id;") {
+			t.Errorf("GenerateQueries() returned query %s with wrong RETURNING clause:\n%s", q.Name, q.Text)
+		}
+	}
+
+	for _, args := range []Args{
+		{Table: "authors", Columns: authorArgs.Columns, Returning: []string{"email"}},
+		{Table: "authors", Columns: authorArgs.Columns, Returning: []string{"id"}, NoReturningClause: true},
+		{Table: "authors", Columns: authorArgs.Columns, Returning: []string{"id"}, Dialect: DialectMySQL},
+	} {
+		err := Validate(args)
+		if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("Validate() returned wrong error: diff -want +got\n%s", diff)
+		}
+	}
+}
+
+func TestGenerateQueriesNoCreateReturning(t *testing.T) {
+	args := authorArgs
+	args.NoCreateReturning = true
+	queries, err := GenerateQueries(args)
+	if err != nil {
+		t.Fatalf("GenerateQueries() returned error: %v", err)
+	}
+	want := "-- name: CreateAuthor :exec\nINSERT INTO authors (\n  name\n) VALUES (\n  ?\n);"
+	if got := queries[4]; got.Text != want {
+		t.Errorf("GenerateQueries() returned wrong create query: %+v", got)
+	}
+}
+
+func TestGenerateView(t *testing.T) {
+	args := authorArgs
+	args.View = true
+	schema, queries, err := Generate(args)
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if schema != "" {
+		t.Errorf("Generate() returned schema for view:\n%s", schema)
+	}
+	for _, stmt := range []string{"INSERT", "UPDATE", "DELETE"} {
+		if strings.Contains(queries, stmt) {
+			t.Errorf("Generate() returned %s statement for view:\n%s", stmt, queries)
+		}
+	}
+
+	args.Upsert = true
+	err = Validate(args)
+	if diff := cmp.Diff(ErrBadArgument, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("Validate() returned wrong error: diff -want +got\n%s", diff)
+	}
+}
@@ -0,0 +1,757 @@
+package sqlcup
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// renderQuery renders the query written by write.
+func renderQuery(write queryWriter, args *Args) Query {
+	b := &strings.Builder{}
+	write(b, args)
+	q := Query{Text: b.String()}
+	// The first line is always the annotation: -- name: <name> :<kind>
+	annotation, _, _ := strings.Cut(q.Text, "\n")
+	if fields := strings.Fields(annotation); len(fields) == 4 {
+		q.Name = fields[2]
+		q.Kind = strings.TrimPrefix(fields[3], ":")
+	}
+	return q
+}
+
+// queryWriter writes a single sqlc query including its annotation.
+type queryWriter func(w io.Writer, args *Args)
+
+// queryWriters returns the writers of all queries for args in the order they appear in the output.
+func queryWriters(args *Args) []queryWriter {
+	var writers []queryWriter
+	if len(args.idColumns()) > 0 {
+		if args.OmitQueries&QueryGet == 0 {
+			writers = append(writers, writeGetQuery, writeExistsQuery)
+		}
+		if args.ForUpdate {
+			writers = append(writers, writeGetForUpdateQuery)
+		}
+	}
+	if args.RowidGet {
+		writers = append(writers, writeGetByRowidQuery)
+	}
+	for _, col := range args.Columns {
+		if col.Unique {
+			col := col
+			writers = append(writers, func(w io.Writer, args *Args) {
+				writeGetByQuery(w, args, col)
+			})
+		}
+	}
+	if args.OmitQueries&QueryList == 0 {
+		writers = append(writers, writeListQuery)
+		if !args.NoCount {
+			writers = append(writers, writeCountQuery)
+		}
+	}
+	for _, name := range args.FilterBy {
+		for _, col := range args.Columns {
+			if col.Name == name {
+				col := col
+				writers = append(writers, func(w io.Writer, args *Args) {
+					writeListByQuery(w, args, col)
+				})
+			}
+		}
+	}
+	if args.Keyset {
+		writers = append(writers, writeListAfterQuery)
+	}
+	if args.GetManyByID {
+		writers = append(writers, writeListByIDsQuery)
+	}
+	if args.View {
+		return writers
+	}
+	if args.OmitQueries&QueryCreate == 0 {
+		writers = append(writers, writeCreateQuery)
+	}
+	if args.BatchInsert {
+		writers = append(writers, writeBatchCreateQuery)
+	}
+	if args.Upsert {
+		writers = append(writers, writeUpsertQuery)
+	}
+	if len(args.idColumns()) > 0 && args.OmitQueries&QueryDelete == 0 {
+		writers = append(writers, writeDeleteQuery)
+	}
+	if args.WithTruncate {
+		writers = append(writers, writeDeleteAllQuery)
+	}
+	if len(args.idColumns()) > 0 {
+		if args.SoftDelete {
+			writers = append(writers, writeRestoreQuery)
+		}
+		// A table that consists of its primary key only has nothing to update.
+		if len(args.updateColumns()) > 0 && args.OmitQueries&QueryUpdate == 0 {
+			writers = append(writers, writeUpdateQuery)
+		}
+		if args.PartialUpdates {
+			for _, col := range args.updateColumns() {
+				if col.UpdateValue != "" {
+					continue
+				}
+				col := col
+				writers = append(writers, func(w io.Writer, args *Args) {
+					writeColumnUpdateQuery(w, args, col)
+				})
+			}
+		}
+	}
+	return writers
+}
+
+//goland:noinspection GoUnhandledErrorResult
+func writeSchema(w io.Writer, args *Args) {
+	if args.WithDrop {
+		fmt.Fprint(w, args.kw("DROP TABLE "))
+		if !args.NoExistsClause {
+			fmt.Fprint(w, args.kw("IF EXISTS "))
+		}
+		fmt.Fprintf(w, "%s;\n", args.schemaTable())
+	}
+	if args.Dialect == DialectPostgres {
+		for _, col := range args.Columns {
+			if len(col.Enum) == 0 || col.Type != args.enumType(col) {
+				continue
+			}
+			// PostgreSQL has no CREATE TYPE IF NOT EXISTS, so the type can only be replaced with WithDrop.
+			if args.WithDrop {
+				fmt.Fprint(w, args.kw("DROP TYPE "))
+				if !args.NoExistsClause {
+					fmt.Fprint(w, args.kw("IF EXISTS "))
+				}
+				fmt.Fprintf(w, "%s;\n", col.Type)
+			}
+			fmt.Fprintf(w, args.kw("CREATE TYPE %s AS ENUM (%s);\n"), col.Type, enumValues(col.Enum))
+		}
+	}
+	if args.TableComment != "" && args.Dialect == DialectSQLite {
+		// SQLite has no comments on tables, but keeps SQL comments in the stored schema.
+		fmt.Fprintf(w, "-- %s\n", args.TableComment)
+	}
+	fmt.Fprint(w, args.kw("CREATE TABLE "))
+	if !args.NoExistsClause {
+		fmt.Fprint(w, args.kw("IF NOT EXISTS "))
+	}
+	fmt.Fprint(w, args.schemaTable())
+	fmt.Fprint(w, " (\n")
+
+	// Table constraints follow the columns.
+	var tableConstraints []string
+	if len(args.idColumns()) > 1 {
+		var names []string
+		for _, name := range args.idColumnNames() {
+			names = append(names, args.schemaIdentifier(name))
+		}
+		tableConstraints = append(tableConstraints, args.kw("PRIMARY KEY (")+strings.Join(names, ", ")+")")
+	}
+	if !args.UniqueAsIndex {
+		for _, cols := range args.UniqueConstraints {
+			var names []string
+			for _, name := range cols {
+				names = append(names, args.schemaIdentifier(name))
+			}
+			tableConstraints = append(tableConstraints, args.kw("UNIQUE (")+strings.Join(names, ", ")+")")
+		}
+	}
+
+	longestName, longestType, longestConstraint := 0, 0, 0
+	for _, col := range args.Columns {
+		if n := len(args.schemaIdentifier(col.Name)); n > longestName {
+			longestName = n
+		}
+		if len(col.Type) > longestType {
+			longestType = len(col.Type)
+		}
+		if n := len(args.schemaConstraint(col)); n > longestConstraint {
+			longestConstraint = n
+		}
+	}
+	for ci, col := range args.Columns {
+		name := args.schemaIdentifier(col.Name)
+		fmt.Fprintf(w, "%s%s ", args.Indent, name)
+		no := longestName - len(name)
+		for i := 0; i < no; i++ {
+			fmt.Fprintf(w, " ")
+		}
+		fmt.Fprintf(w, "%s", col.Type)
+		constraint := args.schemaConstraint(col)
+		comma := ci < len(args.Columns)-1 || len(tableConstraints) > 0
+		if args.AlignConstraints && comma && longestConstraint > 0 {
+			// Pad every line to the same length so that the separating commas line up.
+			constraint += strings.Repeat(" ", longestConstraint-len(constraint))
+		}
+		if constraint != "" {
+			to := longestType - len(col.Type)
+			for i := 0; i < to; i++ {
+				fmt.Fprintf(w, " ")
+			}
+			fmt.Fprintf(w, " %s", constraint)
+		}
+		if comma {
+			fmt.Fprintf(w, ",")
+		}
+		var comments []string
+		if col.Comment != "" && args.Dialect == DialectSQLite {
+			comments = append(comments, col.Comment)
+		}
+		if args.NullableHints && col.Nullable {
+			comments = append(comments, "nullable")
+		}
+		if len(comments) > 0 {
+			fmt.Fprintf(w, " -- %s", strings.Join(comments, "; "))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	for i, constraint := range tableConstraints {
+		fmt.Fprintf(w, "%s%s", args.Indent, constraint)
+		if i < len(tableConstraints)-1 {
+			fmt.Fprintf(w, ",")
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	fmt.Fprintf(w, ")")
+	if args.TableComment != "" && args.Dialect == DialectMySQL {
+		fmt.Fprintf(w, args.kw(" COMMENT=%s"), stringLiteral(args.TableComment))
+	}
+	fmt.Fprintf(w, ";")
+	if args.Dialect == DialectPostgres {
+		writeComments(w, args)
+	}
+
+	type index struct {
+		kind   string
+		prefix string
+		cols   []string
+	}
+	var indexes []index
+	if args.UniqueAsIndex {
+		for _, col := range args.Columns {
+			if col.Unique {
+				indexes = append(indexes, index{"UNIQUE INDEX", "uq", []string{col.Name}})
+			}
+		}
+		for _, cols := range args.UniqueConstraints {
+			indexes = append(indexes, index{"UNIQUE INDEX", "uq", cols})
+		}
+	}
+	for _, cols := range args.Indexes {
+		indexes = append(indexes, index{"INDEX", "idx", cols})
+	}
+	for i, idx := range indexes {
+		if i == 0 {
+			fmt.Fprintf(w, "\n")
+		}
+		fmt.Fprintf(w, "\n")
+		writeIndex(w, args, idx.kind, idx.prefix, idx.cols)
+	}
+}
+
+// writeComments writes COMMENT ON statements for the table and its columns, preceded by an empty line.
+// It writes nothing if there are no comments.
+//
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeComments(w io.Writer, args *Args) {
+	table := args.schemaTable()
+	var comments []string
+	if args.TableComment != "" {
+		comments = append(comments, fmt.Sprintf(args.kw("COMMENT ON TABLE %s IS %s;"), table, stringLiteral(args.TableComment)))
+	}
+	for _, col := range args.Columns {
+		if col.Comment != "" {
+			comments = append(comments, fmt.Sprintf(args.kw("COMMENT ON COLUMN %s.%s IS %s;"), table, args.schemaIdentifier(col.Name), stringLiteral(col.Comment)))
+		}
+	}
+	if len(comments) > 0 {
+		fmt.Fprintf(w, "\n\n%s", strings.Join(comments, "\n"))
+	}
+}
+
+// selectList returns the columns selected by SELECT statements.
+func (args *Args) selectList() string {
+	if !args.ExplicitColumns {
+		return "*"
+	}
+	var names []string
+	for _, col := range args.Columns {
+		names = append(names, args.quoteIdent(col.Name))
+	}
+	return strings.Join(names, ", ")
+}
+
+// kw returns the SQL keywords in s in the configured case.
+// s may contain format verbs, but no identifiers.
+func (args *Args) kw(s string) string {
+	if args.LowercaseKeywords {
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// schemaConstraint returns the constraint of col as it appears in the schema.
+// With UniqueAsIndex, the UNIQUE constraint is replaced by a unique index.
+// MySQL column comments are part of the constraint.
+func (args *Args) schemaConstraint(col Column) string {
+	constraint := col.Constraint
+	if args.UniqueAsIndex && col.Unique {
+		var fields []string
+		for _, f := range strings.Fields(col.Constraint) {
+			if !strings.EqualFold(f, "UNIQUE") {
+				fields = append(fields, f)
+			}
+		}
+		constraint = strings.Join(fields, " ")
+	}
+	if col.Comment != "" && args.Dialect == DialectMySQL {
+		constraint = strings.TrimSpace(constraint + args.kw(" COMMENT ") + stringLiteral(col.Comment))
+	}
+	return constraint
+}
+
+// writeIndex writes a CREATE INDEX statement of the given kind, e.g. "UNIQUE INDEX", for cols.
+// The index is named <prefix>_<table>_<cols>.
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeIndex(w io.Writer, args *Args, kind string, prefix string, cols []string) {
+	fmt.Fprintf(w, args.kw("CREATE %s "), args.kw(kind))
+	// MySQL does not support IF NOT EXISTS for indexes.
+	if !args.NoExistsClause && args.Dialect != DialectMySQL {
+		fmt.Fprint(w, args.kw("IF NOT EXISTS "))
+	}
+	parts := append([]string{prefix, args.Table}, cols...)
+	name := strings.Join(parts, "_")
+	name = strings.NewReplacer(`"`, "", "`", "", " ", "_").Replace(name)
+	var names []string
+	for _, col := range cols {
+		names = append(names, args.schemaIdentifier(col))
+	}
+	fmt.Fprintf(w, args.kw("%s ON %s (%s);"), args.schemaIdentifier(name), args.schemaTable(), strings.Join(names, ", "))
+}
+
+//goland:noinspection GoUnhandledErrorResult
+func writeGetQuery(w io.Writer, args *Args) {
+	writeGetStatement(w, args, args.Names.Get, false)
+}
+
+// writeGetForUpdateQuery writes a query that selects a row by id and locks it until the end of the transaction.
+//goland:noinspection GoUnhandledErrorResult
+func writeGetForUpdateQuery(w io.Writer, args *Args) {
+	writeGetStatement(w, args, args.Names.Get+"ForUpdate", true)
+}
+
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeGetStatement(w io.Writer, args *Args, name string, forUpdate bool) {
+	fmt.Fprintf(w, "-- name: %s :one\n", name)
+	fmt.Fprintf(w, args.kw("SELECT %s FROM %s\n"), args.selectList(), args.queryTable())
+	fmt.Fprintf(w, args.kw("WHERE %s LIMIT 1"), args.readCondition(args.idCondition(args.placeholders())))
+	if forUpdate {
+		fmt.Fprint(w, args.kw(" FOR UPDATE"))
+	}
+	fmt.Fprintf(w, ";")
+}
+
+// writeGetByRowidQuery writes a query that selects a row and its rowid by the implicit rowid of SQLite tables.
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeGetByRowidQuery(w io.Writer, args *Args) {
+	fmt.Fprintf(w, "-- name: %sByRowid :one\n", args.Names.Get)
+	fmt.Fprintf(w, args.kw("SELECT %s, rowid FROM %s\n"), args.selectList(), args.queryTable())
+	fmt.Fprintf(w, args.kw("WHERE %s LIMIT 1;"), args.readCondition("rowid = "+args.placeholders().next()))
+}
+
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeGetByQuery(w io.Writer, args *Args, col Column) {
+	fmt.Fprintf(w, "-- name: %sBy%s :one\n", args.Names.Get, args.upperCamelCase(col.Name))
+	fmt.Fprintf(w, args.kw("SELECT %s FROM %s\n"), args.selectList(), args.queryTable())
+	fmt.Fprintf(w, args.kw("WHERE %s LIMIT 1;"), args.readCondition(args.quoteIdent(col.Name)+" = "+args.placeholders().next()))
+}
+
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeExistsQuery(w io.Writer, args *Args) {
+	fmt.Fprintf(w, "-- name: %sExists :one\n", args.SingularEntity)
+	fmt.Fprintf(w, args.kw("SELECT EXISTS(SELECT 1 FROM %s WHERE %s);"), args.queryTable(), args.readCondition(args.idCondition(args.placeholders())))
+}
+
+//goland:noinspection GoUnhandledErrorResult
+func writeListQuery(w io.Writer, args *Args) {
+	writeListStatement(w, args, args.Names.List, nil)
+}
+
+// writeListByQuery writes a query that lists all rows with the given value in col.
+func writeListByQuery(w io.Writer, args *Args, col Column) {
+	writeListStatement(w, args, args.Names.List+"By"+args.upperCamelCase(col.Name), &col)
+}
+
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeListStatement(w io.Writer, args *Args, name string, filter *Column) {
+	fmt.Fprintf(w, "-- name: %s :many\n", name)
+	if args.Paginate && !args.NamedParams {
+		fmt.Fprintf(w, "-- The last two parameters are LIMIT and OFFSET.\n")
+	}
+	fmt.Fprintf(w, args.kw("SELECT %s FROM %s"), args.selectList(), args.queryTable())
+	p := args.placeholders()
+	var cond, filterName string
+	if filter != nil {
+		cond = args.quoteIdent(filter.Name) + " = " + p.arg(filter.Name)
+		filterName = filter.Name
+	}
+	if cond = args.readCondition(cond); cond != "" {
+		fmt.Fprintf(w, args.kw("\nWHERE %s"), cond)
+	}
+	if orderBy := args.listOrderBy(filterName); orderBy != "" {
+		fmt.Fprintf(w, args.kw("\nORDER BY %s"), args.quoteOrderBy(orderBy))
+	}
+	if args.Paginate {
+		fmt.Fprintf(w, args.kw("\nLIMIT %s OFFSET %s"), p.arg("limit"), p.arg("offset"))
+	}
+	fmt.Fprintf(w, ";")
+}
+
+// writeListAfterQuery writes a query that selects the rows following an id in the order of the ids.
+// Unlike OFFSET, the condition on the id lets the database skip the previous rows by index.
+//
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeListAfterQuery(w io.Writer, args *Args) {
+	id := args.idColumns()[0]
+	fmt.Fprintf(w, "-- name: %sAfter :many\n", args.Names.List)
+	if !args.NamedParams {
+		fmt.Fprintf(w, "-- The last parameter is LIMIT.\n")
+	}
+	fmt.Fprintf(w, args.kw("SELECT %s FROM %s\n"), args.selectList(), args.queryTable())
+	p := args.placeholders()
+	fmt.Fprintf(w, args.kw("WHERE %s\n"), args.readCondition(args.quoteIdent(id.Name)+" > "+p.arg(id.Name)))
+	fmt.Fprintf(w, args.kw("ORDER BY %s\n"), args.quoteIdent(id.Name))
+	fmt.Fprintf(w, args.kw("LIMIT %s;"), p.arg("limit"))
+}
+
+// writeListByIDsQuery writes a query that selects all rows with one of many ids.
+// PostgreSQL compares with an array, all other dialects use sqlc.slice.
+//
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeListByIDsQuery(w io.Writer, args *Args) {
+	id := args.idColumns()[0]
+	fmt.Fprintf(w, "-- name: %sByIDs :many\n", args.Names.List)
+	fmt.Fprintf(w, args.kw("SELECT %s FROM %s\n"), args.selectList(), args.queryTable())
+	var cond string
+	if args.Dialect == DialectPostgres {
+		cond = fmt.Sprintf(args.kw("%s = ANY(sqlc.arg(ids)::%s[])"), args.quoteIdent(id.Name), arrayElementType(id.Type))
+	} else {
+		cond = fmt.Sprintf(args.kw("%s IN (sqlc.slice(ids))"), args.quoteIdent(id.Name))
+	}
+	fmt.Fprintf(w, args.kw("WHERE %s"), args.readCondition(cond))
+	// Like the unfiltered list query, the query selects from all rows.
+	if orderBy := args.listOrderBy(""); orderBy != "" {
+		fmt.Fprintf(w, args.kw("\nORDER BY %s"), args.quoteOrderBy(orderBy))
+	}
+	fmt.Fprintf(w, ";")
+}
+
+// arrayElementType returns the PostgreSQL type of the elements of an array of values of type t.
+// The auto-incrementing pseudo-types are replaced by the integer types they are based on.
+func arrayElementType(t string) string {
+	switch strings.ToUpper(t) {
+	case "SERIAL":
+		return "INTEGER"
+	case "BIGSERIAL":
+		return "BIGINT"
+	case "SMALLSERIAL":
+		return "SMALLINT"
+	}
+	return t
+}
+
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeCountQuery(w io.Writer, args *Args) {
+	fmt.Fprintf(w, "-- name: Count%s :one\n", args.PluralEntity)
+	fmt.Fprintf(w, args.kw("SELECT COUNT(*) FROM %s"), args.queryTable())
+	if cond := args.readCondition(""); cond != "" {
+		fmt.Fprintf(w, args.kw(" WHERE %s"), cond)
+	}
+	fmt.Fprintf(w, ";")
+}
+
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeCreateQuery(w io.Writer, args *Args) {
+	fmt.Fprintf(w, "-- name: %s :%s\n", args.Names.Create, args.CreateKind)
+	writeInsertStatement(w, args, args.insertColumns())
+	writeReturning(w, args, args.NoCreateReturning)
+	fmt.Fprintf(w, ";")
+}
+
+// writeReturning writes the RETURNING clause of an INSERT or UPDATE statement unless omit is true.
+// MySQL does not support RETURNING, so nothing is written for it.
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeReturning(w io.Writer, args *Args, omit bool) {
+	if omit || args.Dialect == DialectMySQL {
+		return
+	}
+	list := args.selectList()
+	if len(args.Returning) > 0 {
+		var names []string
+		for _, name := range args.Returning {
+			names = append(names, args.quoteIdent(name))
+		}
+		list = strings.Join(names, ", ")
+	}
+	fmt.Fprintf(w, args.kw("\nRETURNING %s"), list)
+}
+
+// writeBatchCreateQuery writes a query that inserts many rows at once.
+// PostgreSQL uses the COPY protocol, all other dialects use batched statements.
+//
+//goland:noinspection GoUnhandledErrorResult
+func writeBatchCreateQuery(w io.Writer, args *Args) {
+	kind := "batchexec"
+	if args.Dialect == DialectPostgres {
+		kind = "copyfrom"
+	}
+	fmt.Fprintf(w, "-- name: Create%s :%s\n", args.PluralEntity, kind)
+	writeInsertStatement(w, args, args.insertColumns())
+	fmt.Fprintf(w, ";")
+}
+
+// writeInsertStatement writes an INSERT statement that sets cols, without a trailing semicolon.
+//
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeInsertStatement(w io.Writer, args *Args, cols []Column) {
+	var values []string
+	p := args.placeholders()
+	for range cols {
+		values = append(values, p.next())
+	}
+	writeInsertValues(w, args, cols, values)
+}
+
+// writeInsertValues writes an INSERT statement that sets cols to values, without a trailing semicolon.
+//
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeInsertValues(w io.Writer, args *Args, cols []Column, values []string) {
+	fmt.Fprintf(w, args.kw("INSERT INTO %s (\n"), args.queryTable())
+	var names []string
+	for _, col := range cols {
+		names = append(names, args.quoteIdent(col.Name))
+	}
+	writeList(w, args, names)
+	fmt.Fprint(w, args.kw(") VALUES (\n"))
+	writeList(w, args, values)
+	fmt.Fprintf(w, ")")
+}
+
+// writeList writes the comma-separated items on an indented line followed by a newline.
+// With MaxLineWidth, the items are wrapped onto further indented lines that do not exceed it,
+// unless a single item is wider.
+//
+//goland:noinspection GoUnhandledErrorResult
+func writeList(w io.Writer, args *Args, items []string) {
+	width := 0
+	for i, item := range items {
+		switch {
+		case i == 0:
+			fmt.Fprint(w, args.Indent)
+			width = len(args.Indent)
+		case args.MaxLineWidth > 0 && width+len(", ")+len(item)+len(",") > args.MaxLineWidth:
+			fmt.Fprintf(w, ",\n%s", args.Indent)
+			width = len(args.Indent)
+		default:
+			fmt.Fprint(w, ", ")
+			width += len(", ")
+		}
+		fmt.Fprint(w, item)
+		width += len(item)
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeUpsertQuery(w io.Writer, args *Args) {
+	returning := args.returning()
+	var mode string
+	if returning {
+		mode = ":one"
+	} else {
+		mode = ":exec"
+	}
+	fmt.Fprintf(w, "-- name: Upsert%s %s\n", args.SingularEntity, mode)
+	var cols []Column
+	for _, col := range args.Columns {
+		if !col.ReadOnly {
+			cols = append(cols, col)
+		}
+	}
+	writeInsertStatement(w, args, cols)
+	fmt.Fprintf(w, "\n")
+
+	conflict := make(map[string]bool)
+	for _, name := range args.ConflictColumns {
+		conflict[name] = true
+	}
+	var assignments []string
+	for _, col := range args.Columns {
+		name := args.quoteIdent(col.Name)
+		switch {
+		case conflict[col.Name]:
+		case col.UpdateValue != "":
+			assignments = append(assignments, fmt.Sprintf("%s = %s", name, col.UpdateValue))
+		case col.ReadOnly:
+		case args.Dialect == DialectMySQL:
+			assignments = append(assignments, fmt.Sprintf(args.kw("%s = VALUES(%s)"), name, name))
+		default:
+			assignments = append(assignments, fmt.Sprintf("%s = excluded.%s", name, name))
+		}
+	}
+	if args.Dialect == DialectMySQL {
+		if len(assignments) == 0 {
+			// Assigning the conflict column to itself turns the upsert into a no-op for existing rows.
+			name := args.quoteIdent(args.ConflictColumns[0])
+			assignments = append(assignments, fmt.Sprintf("%s = %s", name, name))
+		}
+		fmt.Fprint(w, args.kw("ON DUPLICATE KEY UPDATE\n"))
+	} else {
+		var names []string
+		for _, name := range args.ConflictColumns {
+			names = append(names, args.quoteIdent(name))
+		}
+		fmt.Fprintf(w, args.kw("ON CONFLICT (%s) "), strings.Join(names, ", "))
+		if len(assignments) == 0 {
+			fmt.Fprint(w, args.kw("DO NOTHING"))
+		} else {
+			fmt.Fprint(w, args.kw("DO UPDATE SET\n"))
+		}
+	}
+	for i, a := range assignments {
+		if i < len(assignments)-1 {
+			fmt.Fprintf(w, "%s%s,\n", args.Indent, a)
+		} else {
+			fmt.Fprintf(w, "%s%s", args.Indent, a)
+		}
+	}
+	writeReturning(w, args, args.NoReturningClause)
+	fmt.Fprintf(w, ";")
+}
+
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeDeleteQuery(w io.Writer, args *Args) {
+	fmt.Fprintf(w, "-- name: %s :exec\n", args.Names.Delete)
+	if args.SoftDelete {
+		fmt.Fprintf(w, args.kw("UPDATE %s\n"), args.queryTable())
+		fmt.Fprintf(w, args.kw("SET %s = CURRENT_TIMESTAMP\n"), args.quoteIdent(SoftDeleteColumn))
+	} else {
+		fmt.Fprintf(w, args.kw("DELETE FROM %s\n"), args.queryTable())
+	}
+	fmt.Fprintf(w, args.kw("WHERE %s;"), args.idCondition(args.placeholders()))
+}
+
+// writeDeleteAllQuery writes a query that deletes all rows, regardless of SoftDelete.
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeDeleteAllQuery(w io.Writer, args *Args) {
+	fmt.Fprintf(w, "-- name: DeleteAll%s :exec\n", args.PluralEntity)
+	if args.Dialect == DialectSQLite {
+		// SQLite has no TRUNCATE, but optimizes an unqualified DELETE.
+		fmt.Fprintf(w, args.kw("DELETE FROM %s;"), args.queryTable())
+	} else {
+		fmt.Fprintf(w, args.kw("TRUNCATE TABLE %s;"), args.queryTable())
+	}
+}
+
+//goland:noinspection GoUnhandledErrorResult,SqlNoDataSourceInspection
+func writeRestoreQuery(w io.Writer, args *Args) {
+	fmt.Fprintf(w, "-- name: Restore%s :exec\n", args.SingularEntity)
+	fmt.Fprintf(w, args.kw("UPDATE %s\n"), args.queryTable())
+	fmt.Fprintf(w, args.kw("SET %s = NULL\n"), args.quoteIdent(SoftDeleteColumn))
+	fmt.Fprintf(w, args.kw("WHERE %s;"), args.idCondition(args.placeholders()))
+}
+
+//goland:noinspection GoUnhandledErrorResult
+func writeUpdateQuery(w io.Writer, args *Args) {
+	writeUpdateStatement(w, args, args.Names.Update, args.updateColumns(), args.CoalesceUpdate)
+}
+
+// writeColumnUpdateQuery writes a query that only updates col and the columns with an UpdateValue.
+func writeColumnUpdateQuery(w io.Writer, args *Args, col Column) {
+	cols := []Column{col}
+	for _, c := range args.updateColumns() {
+		if c.UpdateValue != "" {
+			cols = append(cols, c)
+		}
+	}
+	writeUpdateStatement(w, args, args.Names.Update+args.upperCamelCase(col.Name), cols, false)
+}
+
+// writeUpdateStatement writes a query that sets cols of the row with the given id.
+// If coalesce is true, columns stay unchanged if their parameter is NULL.
+//
+//goland:noinspection GoUnhandledErrorResult
+func writeUpdateStatement(w io.Writer, args *Args, name string, cols []Column, coalesce bool) {
+	fmt.Fprintf(w, "-- name: %s :%s\n", name, args.UpdateKind)
+	fmt.Fprintf(w, args.kw("UPDATE %s\n"), args.queryTable())
+	fmt.Fprint(w, args.kw("SET\n"))
+	p := args.placeholders()
+	for i, col := range cols {
+		value := col.UpdateValue
+		if value == "" {
+			value = p.next()
+			if coalesce {
+				value = fmt.Sprintf(args.kw("COALESCE(%s, %s)"), value, args.quoteIdent(col.Name))
+			}
+		}
+		if i < len(cols)-1 {
+			fmt.Fprintf(w, "%s%s = %s,\n", args.Indent, args.quoteIdent(col.Name), value)
+		} else {
+			fmt.Fprintf(w, "%s%s = %s\n", args.Indent, args.quoteIdent(col.Name), value)
+		}
+	}
+	fmt.Fprintf(w, args.kw("WHERE %s"), args.idCondition(p))
+	writeReturning(w, args, args.NoReturningClause)
+	fmt.Fprintf(w, ";")
+}
+
+// writeFixture writes an INSERT statement that sets all insertable columns to sample values for the nth row.
+//
+//goland:noinspection GoUnhandledErrorResult
+func writeFixture(w io.Writer, args *Args, n int) {
+	cols := args.insertColumns()
+	var values []string
+	for _, col := range cols {
+		values = append(values, args.sampleValue(col, n))
+	}
+	writeInsertValues(w, args, cols, values)
+	fmt.Fprintf(w, ";")
+}
+
+// sampleValue returns a literal for the nth sample row that matches the type of col.
+// Numbers and texts contain n, so that the rows of unique columns differ.
+func (args *Args) sampleValue(col Column, n int) string {
+	if len(col.Enum) > 0 {
+		return stringLiteral(col.Enum[(n-1)%len(col.Enum)])
+	}
+	t := strings.ToUpper(col.Type)
+	switch {
+	case t == "BOOLEAN" || t == "BOOL" || t == "TINYINT(1)":
+		if args.Dialect == DialectPostgres {
+			return args.kw("FALSE")
+		}
+		return "0"
+	case strings.Contains(t, "INT") || strings.Contains(t, "SERIAL") || strings.HasPrefix(t, "DECIMAL") ||
+		strings.HasPrefix(t, "NUMERIC") || t == "REAL" || t == "FLOAT" || strings.HasPrefix(t, "DOUBLE"):
+		return strconv.Itoa(n)
+	case strings.HasPrefix(t, "DATE") || strings.HasPrefix(t, "TIME"):
+		return args.kw("CURRENT_TIMESTAMP")
+	case t == "UUID" || t == "CHAR(36)":
+		return fmt.Sprintf("'00000000-0000-0000-0000-%012d'", n)
+	case t == "JSON" || t == "JSONB":
+		return "'{}'"
+	case t == "BLOB" || t == "BYTEA":
+		if args.Dialect == DialectPostgres {
+			return `'\x00'`
+		}
+		return "X'00'"
+	}
+	return stringLiteral(fmt.Sprintf("%s %d", col.Name, n))
+}